@@ -15,11 +15,16 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 
+	"github.com/Adembc/lazyssh/internal/adapters/cli"
+	"github.com/Adembc/lazyssh/internal/adapters/data/settings"
 	"github.com/Adembc/lazyssh/internal/adapters/data/ssh_config_file"
+	"github.com/Adembc/lazyssh/internal/adapters/data/state"
 	"github.com/Adembc/lazyssh/internal/logger"
 
 	"github.com/Adembc/lazyssh/internal/adapters/ui"
@@ -50,21 +55,51 @@ func main() {
 	}
 	sshConfigFile := filepath.Join(home, ".ssh", "config")
 	metaDataFile := filepath.Join(home, ".lazyssh", "metadata.json")
+	settingsFile := filepath.Join(home, ".lazyssh", "config.yaml")
+	stateFile := filepath.Join(home, ".lazyssh", "state.json")
 
-	serverRepo := ssh_config_file.NewRepository(log, sshConfigFile, metaDataFile)
-	serverService := services.NewServerService(log, serverRepo)
-	tui := ui.NewTUI(log, serverService, version, gitCommit)
+	appSettings, err := settings.Load(settingsFile)
+	if err != nil {
+		log.Errorw("failed to load settings", "error", err)
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		//nolint:gocritic // exitAfterDefer: ensure immediate exit on unrecoverable error
+		os.Exit(1)
+	}
+
+	serverRepo := ssh_config_file.NewRepository(log, sshConfigFile, metaDataFile, appSettings)
+	serverService := services.NewServerService(log, serverRepo, settings.NewSearchStore(settingsFile))
+	tui := ui.NewTUI(log, serverService, state.NewStore(stateFile), version, gitCommit)
 
+	var fresh bool
 	rootCmd := &cobra.Command{
 		Use:   ui.AppName,
 		Short: "Lazy SSH server picker TUI",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return tui.Run()
+			return tui.Fresh(fresh).Run()
 		},
 	}
+	rootCmd.Flags().BoolVar(&fresh, "fresh", false, "start with the default sort, search and layout instead of restoring the last session")
+	rootCmd.AddCommand(
+		cli.NewListCommand(serverService),
+		cli.NewConnectCommand(serverService),
+		cli.NewAddCommand(serverService),
+		cli.NewEditCommand(serverService),
+		cli.NewRemoveCommand(serverService),
+		cli.NewTagCommand(serverService),
+		cli.NewPinCommand(serverService),
+		cli.NewDoctorCommand(serverService),
+		cli.NewAliasesCommand(serverService),
+		cli.NewCompletionCommand(),
+	)
 	rootCmd.SilenceUsage = true
+	rootCmd.SilenceErrors = true
 
 	if err := rootCmd.Execute(); err != nil {
+		// ssh has already reported why it failed; pass its exit status on, as ssh itself would.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -0,0 +1,60 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/spf13/cobra"
+)
+
+// NewAliasesCommand returns `lazyssh aliases`, which prints every server alias, one per line.
+func NewAliasesCommand(ss ports.ServerService) *cobra.Command {
+	var tags bool
+	cmd := &cobra.Command{
+		Use:   "aliases",
+		Short: "Print server aliases, one per line",
+		Long: `Print the alias of every server, including the extra aliases of a Host line, sorted
+and one per line. The config is scanned rather than fully parsed, so this stays fast on
+configs with thousands of hosts and suits shell completion and scripts.`,
+		Example: `  lazyssh aliases | fzf | xargs -o lazyssh connect
+  lazyssh aliases --tags`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list, what := ss.ListAliases, "aliases"
+			if tags {
+				list, what = ss.ListTags, "tags"
+			}
+			names, err := list()
+			if err != nil {
+				return fmt.Errorf("list %s: %w", what, err)
+			}
+			return printLines(cmd.OutOrStdout(), names)
+		},
+	}
+	cmd.Flags().BoolVar(&tags, "tags", false, "print the tags in use instead")
+	return cmd
+}
+
+func printLines(w io.Writer, lines []string) error {
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/spf13/cobra"
+)
+
+// NewCompletionCommand returns `lazyssh completion bash|zsh|fish`, which prints a shell
+// completion script. It replaces cobra's default command so it can describe what is completed.
+func NewCompletionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion bash|zsh|fish",
+		Short: "Print a shell completion script",
+		Long: `Print a completion script for bash, zsh or fish. Besides subcommands and flags it
+completes server aliases for connect, edit, rm, tag and pin, and tags for --tag, --add
+and --remove, read from the SSH config each time so new servers show up right away.`,
+		Example: `  # bash, in ~/.bashrc (needs the bash-completion package)
+  source <(lazyssh completion bash)
+
+  # zsh, in ~/.zshrc after compinit
+  source <(lazyssh completion zsh)
+
+  # fish
+  lazyssh completion fish > ~/.config/fish/completions/lazyssh.fish`,
+		ValidArgs:             []string{"bash", "zsh", "fish"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, w := cmd.Root(), cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(w, true)
+			case "zsh":
+				return root.GenZshCompletion(w)
+			default:
+				return root.GenFishCompletion(w, true)
+			}
+		},
+	}
+}
+
+// completeAliases completes the aliases not given yet. With maxArgs above zero, only the
+// first maxArgs arguments are aliases and nothing is completed after them.
+func completeAliases(ss ports.ServerService, maxArgs int) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if maxArgs > 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		aliases, err := ss.ListAliases()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		completions := make([]cobra.Completion, 0, len(aliases))
+		for _, alias := range aliases {
+			if strings.HasPrefix(alias, toComplete) && !slices.Contains(args, alias) {
+				completions = append(completions, alias)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeTags completes the tags in use. Tag flags may take a comma-separated list, so
+// only the tag after the last comma is completed and the ones before it are kept.
+func completeTags(ss ports.ServerService) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		tags, err := ss.ListTags()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		prefix, partial := "", toComplete
+		if i := strings.LastIndex(toComplete, ","); i >= 0 {
+			prefix, partial = toComplete[:i+1], toComplete[i+1:]
+		}
+		given := strings.Split(prefix, ",")
+		completions := make([]cobra.Completion, 0, len(tags))
+		for _, tag := range tags {
+			if strings.HasPrefix(tag, partial) && !slices.Contains(given, tag) {
+				completions = append(completions, prefix+tag)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// registerTagCompletion completes the named flags of cmd with completeTags.
+func registerTagCompletion(cmd *cobra.Command, ss ports.ServerService, names ...string) {
+	for _, name := range names {
+		// Registering only fails for an unknown or already registered flag.
+		cobra.CheckErr(cmd.RegisterFlagCompletionFunc(name, completeTags(ss)))
+	}
+}
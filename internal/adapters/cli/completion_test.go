@@ -0,0 +1,65 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/spf13/cobra"
+)
+
+func (f *fakeService) ListAliases() ([]string, error) {
+	aliases := make([]string, 0, len(f.servers))
+	for _, server := range f.servers {
+		aliases = append(aliases, server.Alias)
+	}
+	return aliases, nil
+}
+
+func (f *fakeService) ListTags() ([]string, error) {
+	return []string{"db", "eu", "prod"}, nil
+}
+
+func TestCompletion(t *testing.T) {
+	ss := &fakeService{servers: []domain.Server{{Alias: "web"}, {Alias: "web2"}, {Alias: "db"}}}
+
+	tests := []struct {
+		name       string
+		complete   cobra.CompletionFunc
+		args       []string
+		toComplete string
+		want       []cobra.Completion
+	}{
+		{"aliases by prefix", completeAliases(ss, 0), nil, "we", []cobra.Completion{"web", "web2"}},
+		{"aliases not given yet", completeAliases(ss, 0), []string{"web"}, "", []cobra.Completion{"web2", "db"}},
+		{"only the first argument", completeAliases(ss, 1), []string{"web"}, "", nil},
+		{"tags", completeTags(ss), nil, "", []cobra.Completion{"db", "eu", "prod"}},
+		{"tag after a comma", completeTags(ss), nil, "eu,", []cobra.Completion{"eu,db", "eu,prod"}},
+		{"tag prefix after a comma", completeTags(ss), nil, "prod,d", []cobra.Completion{"prod,db"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, directive := tt.complete(&cobra.Command{}, tt.args, tt.toComplete)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("completions = %q, want %q", got, tt.want)
+			}
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("directive = %d, want NoFileComp", directive)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// NewConnectCommand returns `lazyssh connect <alias|query> [-- ssh args]`, which connects to a
+// server without opening the TUI.
+func NewConnectCommand(ss ports.ServerService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "connect [alias|query] [-- ssh args...]",
+		Short: "Connect to a server by alias, or pick one of the servers a query matches",
+		Long: `Connect to a server over ssh. An exact alias connects right away; otherwise the
+query is searched like in the search bar and, if several servers match, a picker lets
+you narrow it down and choose one with the arrow keys and Enter. Arguments after --
+are passed to ssh, after the alias.`,
+		Example: `  lazyssh connect web
+  lazyssh connect prod db
+  lazyssh connect web -- -L 8080:localhost:80
+  lazyssh connect web -- uptime`,
+		ValidArgsFunction: completeAliases(ss, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var sshArgs []string
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				args, sshArgs = args[:dash], args[dash:]
+			}
+			server, err := resolveServer(ss, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			return ss.SSH(server.Alias, sshArgs...)
+		},
+	}
+}
+
+// resolveServer finds the server to connect to: the one with query as alias, the only one
+// matching query, or the one picked among several matches.
+func resolveServer(ss ports.ServerService, query string) (domain.Server, error) {
+	query = strings.TrimSpace(query)
+	servers, err := ss.ListServers("")
+	if err != nil {
+		return domain.Server{}, fmt.Errorf("list servers: %w", err)
+	}
+	if server, ok := findServer(servers, query); ok {
+		return server, nil
+	}
+
+	results, err := ss.SearchServers(query)
+	if err != nil {
+		return domain.Server{}, fmt.Errorf("search servers: %w", err)
+	}
+	switch {
+	case len(results) == 0:
+		return domain.Server{}, fmt.Errorf("no server matches %q", query)
+	case len(results) == 1:
+		return results[0].Server, nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stderr.Fd())) {
+		aliases := make([]string, 0, len(results))
+		for _, result := range results {
+			aliases = append(aliases, result.Server.Alias)
+		}
+		return domain.Server{}, fmt.Errorf("%q matches %d servers: %s", query, len(results), strings.Join(aliases, ", "))
+	}
+	return pickServer(ss, query)
+}
+
+// findServer returns the server that has alias among its aliases.
+func findServer(servers []domain.Server, alias string) (domain.Server, bool) {
+	for _, server := range servers {
+		for _, a := range append([]string{server.Alias}, server.Aliases...) {
+			if a == alias {
+				return server, true
+			}
+		}
+	}
+	return domain.Server{}, false
+}
+
+// pickServer runs the picker on the terminal, drawing on stderr so stdout stays clean.
+func pickServer(ss ports.ServerService, query string) (domain.Server, error) {
+	p, err := newPicker(query, ss.SearchServers)
+	if err != nil {
+		return domain.Server{}, err
+	}
+
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return domain.Server{}, fmt.Errorf("set up terminal: %w", err)
+	}
+	defer func() {
+		p.clear(os.Stderr)
+		_ = term.Restore(fd, state)
+	}()
+
+	buf := make([]byte, 64)
+	for {
+		p.render(os.Stderr)
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return domain.Server{}, fmt.Errorf("read terminal: %w", err)
+		}
+		server, err := p.handleKey(buf[:n])
+		if errors.Is(err, errPickerCanceled) {
+			return domain.Server{}, errors.New("no server chosen")
+		}
+		if err != nil {
+			return domain.Server{}, err
+		}
+		if server != nil {
+			return *server, nil
+		}
+	}
+}
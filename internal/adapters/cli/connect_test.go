@@ -0,0 +1,90 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// SearchServers matches servers whose alias contains query.
+func (f *fakeService) SearchServers(query string) ([]domain.SearchResult, error) {
+	results := make([]domain.SearchResult, 0)
+	for _, server := range f.servers {
+		if strings.Contains(server.Alias, query) {
+			results = append(results, domain.SearchResult{Server: server})
+		}
+	}
+	return results, nil
+}
+
+func TestResolveServer(t *testing.T) {
+	ss := &fakeService{servers: []domain.Server{
+		{Alias: "web", Aliases: []string{"web", "www"}},
+		{Alias: "web-staging"},
+		{Alias: "db"},
+	}}
+
+	tests := []struct {
+		query, want string
+	}{
+		{"web", "web"},
+		{"www", "web"},
+		{"stag", "web-staging"},
+		{"d", "db"},
+	}
+	for _, tt := range tests {
+		if got, err := resolveServer(ss, tt.query); err != nil || got.Alias != tt.want {
+			t.Errorf("resolveServer(%q) = %q, %v, want %q", tt.query, got.Alias, err, tt.want)
+		}
+	}
+	// Tests don't run on a terminal, so several matches can't be picked from.
+	for _, query := range []string{"nope", "we"} {
+		if _, err := resolveServer(ss, query); err == nil {
+			t.Errorf("resolveServer(%q) succeeded, want error", query)
+		}
+	}
+}
+
+func TestPickerKeys(t *testing.T) {
+	ss := &fakeService{servers: []domain.Server{{Alias: "web1"}, {Alias: "web2"}, {Alias: "db"}}}
+	p, err := newPicker("w", ss.SearchServers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{"e", "\x1b[B", "\x1b[B", "\x1b[A", "\x1b[B"}
+	for _, key := range keys {
+		if server, err := p.handleKey([]byte(key)); server != nil || err != nil {
+			t.Fatalf("handleKey(%q) = %v, %v before Enter", key, server, err)
+		}
+	}
+	if server, err := p.handleKey([]byte("\r")); err != nil || server == nil || server.Alias != "web2" {
+		t.Errorf("Enter chose %v, %v, want web2", server, err)
+	}
+
+	for _, key := range []string{"\x7f", "\x7f", "d", "\x15"} {
+		_, _ = p.handleKey([]byte(key))
+	}
+	if string(p.query) != "" || len(p.results) != 3 {
+		t.Errorf("after editing, query = %q with %d results, want empty with all servers", string(p.query), len(p.results))
+	}
+	if _, err := p.handleKey([]byte("\x1b")); !errors.Is(err, errPickerCanceled) {
+		t.Errorf("Esc returned %v, want errPickerCanceled", err)
+	}
+}
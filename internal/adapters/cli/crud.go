@@ -0,0 +1,270 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/Adembc/lazyssh/internal/core/services"
+	"github.com/spf13/cobra"
+)
+
+// NewAddCommand returns `lazyssh add <alias>`, which adds a server to the SSH config.
+func NewAddCommand(ss ports.ServerService) *cobra.Command {
+	var file string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "add <alias> --host <host> [flags]",
+		Short: "Add a server",
+		Long: `Add a server to the SSH config, validated and written like the add form does:
+the config is backed up first and replaced atomically. Every SSH option of the form
+has a flag; options that may appear several times, such as --local-forward, repeat.`,
+		Example: `  lazyssh add web --host 10.0.0.5 --user deploy --tag prod
+  lazyssh add db --host db.internal --proxy-jump bastion --local-forward 5432:localhost:5432
+  lazyssh add web --host 10.0.0.5 --set-env APP_ENV=prod --dry-run`,
+		Args: cobra.ExactArgs(1),
+	}
+	sf := addServerFlags(cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		server := domain.Server{Alias: args[0], Port: sf.port}
+		if err := services.ValidateField("Alias", server.Alias); err != nil {
+			return err
+		}
+		if err := sf.apply(cmd.Flags(), &server); err != nil {
+			return err
+		}
+		if err := services.ValidateField("Host", server.Host); err != nil {
+			return fmt.Errorf("--host: %w", err)
+		}
+		if file != "" {
+			path, err := filepath.Abs(file)
+			if err != nil {
+				return fmt.Errorf("--file: %w", err)
+			}
+			server.SourceFile = path
+		}
+		return runChange(cmd.OutOrStdout(), ss, dryRun, "added server "+server.Alias, func() error {
+			return ss.AddServer(server)
+		})
+	}
+	cmd.Flags().StringVar(&file, "file", "", "config file to add the server to, the main config or one it includes (default the main config)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the changes as a diff instead of making them")
+	registerTagCompletion(cmd, ss, "tag")
+	return cmd
+}
+
+// NewEditCommand returns `lazyssh edit <alias>`, which changes the settings given as flags.
+func NewEditCommand(ss ports.ServerService) *cobra.Command {
+	var rename string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "edit <alias> [flags]",
+		Short: "Change settings of a server",
+		Long: `Change the settings of a server given as flags, leaving the others as they are.
+An empty value removes a setting, e.g. --proxy-jump ''. Repeatable flags such as --tag
+or --local-forward replace all values of the setting.`,
+		Example: `  lazyssh edit web --user admin --port 2222
+  lazyssh edit web --rename web-old --dry-run
+  lazyssh edit db --local-forward 5432:localhost:5432 --local-forward 6379:localhost:6379`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeAliases(ss, 1),
+	}
+	sf := addServerFlags(cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		servers, err := findServers(ss, args)
+		if err != nil {
+			return err
+		}
+		server := servers[0]
+		newServer := server
+		if cmd.Flags().Changed("rename") {
+			if err := services.ValidateField("Alias", rename); err != nil {
+				return fmt.Errorf("--rename: %w", err)
+			}
+			newServer.Alias = rename
+		}
+		if err := sf.apply(cmd.Flags(), &newServer); err != nil {
+			return err
+		}
+		return runChange(cmd.OutOrStdout(), ss, dryRun, "updated server "+newServer.Alias, func() error {
+			return ss.UpdateServer(server, newServer)
+		})
+	}
+	cmd.Flags().StringVar(&rename, "rename", "", "new alias of the server")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the changes as a diff instead of making them")
+	registerTagCompletion(cmd, ss, "tag")
+	return cmd
+}
+
+// NewRemoveCommand returns `lazyssh rm <alias>...`, which deletes servers from the SSH config.
+func NewRemoveCommand(ss ports.ServerService) *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:     "rm <alias>...",
+		Aliases: []string{"remove"},
+		Short:   "Delete servers",
+		Example: `  lazyssh rm web
+  lazyssh rm web db --dry-run`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeAliases(ss, 0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			servers, err := findServers(ss, args)
+			if err != nil {
+				return err
+			}
+			return runChange(cmd.OutOrStdout(), ss, dryRun, fmt.Sprintf("deleted %s", countServers(servers)), func() error {
+				if len(servers) == 1 {
+					return ss.DeleteServer(servers[0])
+				}
+				return ss.DeleteServers(servers)
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the changes as a diff instead of making them")
+	return cmd
+}
+
+// NewTagCommand returns `lazyssh tag <alias>...`, which adds and removes tags of servers.
+func NewTagCommand(ss ports.ServerService) *cobra.Command {
+	var add, remove []string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "tag <alias>... [--add tags] [--remove tags]",
+		Short: "Add or remove tags of servers",
+		Long: `Add tags to servers and remove tags from them, keeping their other tags.
+Tags compare case-insensitively; several tags can be given separated by commas.`,
+		Example: `  lazyssh tag web db --add prod,eu
+  lazyssh tag web --add prod --remove staging`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeAliases(ss, 0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(add) == 0 && len(remove) == 0 {
+				return fmt.Errorf("give the tags to change with --add or --remove")
+			}
+			for _, tag := range nonEmpty(add) {
+				if err := services.ValidateField("Tag", tag); err != nil {
+					return fmt.Errorf("--add: %w", err)
+				}
+			}
+			servers, err := findServers(ss, args)
+			if err != nil {
+				return err
+			}
+			newServers := make([]domain.Server, len(servers))
+			for i, server := range servers {
+				newServers[i] = server
+				newServers[i].Tags = services.EditTags(server.Tags, nonEmpty(add), nonEmpty(remove))
+			}
+			return runChange(cmd.OutOrStdout(), ss, dryRun, fmt.Sprintf("updated tags of %s", countServers(servers)), func() error {
+				if len(servers) == 1 {
+					return ss.UpdateServer(servers[0], newServers[0])
+				}
+				return ss.UpdateServers(servers, newServers)
+			})
+		},
+	}
+	cmd.Flags().StringSliceVar(&add, "add", nil, "tags to add")
+	cmd.Flags().StringSliceVar(&remove, "remove", nil, "tags to remove")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the changes as a diff instead of making them")
+	registerTagCompletion(cmd, ss, "add", "remove")
+	return cmd
+}
+
+// NewPinCommand returns `lazyssh pin <alias>...`, which pins servers to the top of the list.
+func NewPinCommand(ss ports.ServerService) *cobra.Command {
+	var unpin, dryRun bool
+	cmd := &cobra.Command{
+		Use:   "pin <alias>...",
+		Short: "Pin servers to the top of the server list, or unpin them",
+		Example: `  lazyssh pin web db
+  lazyssh pin web --unpin`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeAliases(ss, 0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			servers, err := findServers(ss, args)
+			if err != nil {
+				return err
+			}
+			aliases := make([]string, len(servers))
+			for i, server := range servers {
+				aliases[i] = server.Alias
+			}
+			done := "pinned " + countServers(servers)
+			if unpin {
+				done = "unpinned " + countServers(servers)
+			}
+			return runChange(cmd.OutOrStdout(), ss, dryRun, done, func() error {
+				if len(aliases) == 1 {
+					return ss.SetPinned(aliases[0], !unpin)
+				}
+				return ss.SetPinnedServers(aliases, !unpin)
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&unpin, "unpin", false, "unpin the servers instead")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the changes as a diff instead of making them")
+	return cmd
+}
+
+// runChange makes a change and reports it on w with done. With dryRun nothing is written;
+// the diff of the files the change would modify is printed instead.
+func runChange(w io.Writer, ss ports.ServerService, dryRun bool, done string, change func() error) error {
+	if !dryRun {
+		if err := change(); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(w, done)
+		return err
+	}
+
+	diff, err := ss.Preview(change)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		diff = "no changes\n"
+	}
+	_, err = io.WriteString(w, diff)
+	return err
+}
+
+// findServers returns the servers with the given aliases, in that order.
+func findServers(ss ports.ServerService, aliases []string) ([]domain.Server, error) {
+	servers, err := ss.ListServers("")
+	if err != nil {
+		return nil, fmt.Errorf("list servers: %w", err)
+	}
+	found := make([]domain.Server, 0, len(aliases))
+	for _, alias := range aliases {
+		server, ok := findServer(servers, alias)
+		if !ok {
+			return nil, fmt.Errorf("no server with alias %q", alias)
+		}
+		found = append(found, server)
+	}
+	return found, nil
+}
+
+// countServers names a single server by its alias and several by their number.
+func countServers(servers []domain.Server) string {
+	if len(servers) == 1 {
+		return "server " + servers[0].Alias
+	}
+	return fmt.Sprintf("%d servers", len(servers))
+}
@@ -0,0 +1,112 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/spf13/cobra"
+)
+
+func TestOptionFlags(t *testing.T) {
+	serverType := reflect.TypeOf(domain.Server{})
+	seen := make(map[string]bool)
+	for _, option := range optionFlags {
+		field, ok := serverType.FieldByName(option.field)
+		if !ok {
+			t.Errorf("--%s: domain.Server has no field %s", option.name, option.field)
+			continue
+		}
+		if kind := field.Type.Kind(); kind != reflect.String && field.Type != reflect.TypeOf([]string{}) {
+			t.Errorf("--%s: field %s is a %s, want string or []string", option.name, option.field, kind)
+		}
+		if seen[option.name] {
+			t.Errorf("--%s is listed twice", option.name)
+		}
+		seen[option.name] = true
+	}
+}
+
+func TestServerFlagsApply(t *testing.T) {
+	base := domain.Server{
+		Alias:        "web",
+		Host:         "10.0.0.1",
+		Port:         22,
+		Tags:         []string{"prod"},
+		Fields:       map[string]string{"owner": "ops"},
+		ProxyJump:    "bastion",
+		LocalForward: []string{"8080:localhost:80"},
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    func(s *domain.Server)
+		wantErr bool
+	}{
+		{"no flags", nil, func(*domain.Server) {}, false},
+		{
+			"basic and advanced options",
+			[]string{"--user", "deploy", "--port", "2222", "--set-env", "A=1", "--set-env", "B=2", "--compression", "yes"},
+			func(s *domain.Server) {
+				s.User, s.Port, s.SetEnv, s.Compression = "deploy", 2222, []string{"A=1", "B=2"}, "yes"
+			},
+			false,
+		},
+		{
+			"empty values remove settings",
+			[]string{"--proxy-jump", "", "--local-forward", "", "--field", "owner=", "--tag", ""},
+			func(s *domain.Server) {
+				s.ProxyJump, s.LocalForward, s.Fields, s.Tags = "", nil, map[string]string{}, nil
+			},
+			false,
+		},
+		{"fields merge", []string{"--field", "rack=b2"}, func(s *domain.Server) {
+			s.Fields = map[string]string{"owner": "ops", "rack": "b2"}
+		}, false},
+		{"invalid port", []string{"--port", "0"}, nil, true},
+		{"invalid user", []string{"--user", "1root"}, nil, true},
+		{"invalid forward", []string{"--local-forward", "nope"}, nil, true},
+		{"invalid option value", []string{"--connect-timeout", "-1"}, nil, true},
+		{"invalid field", []string{"--field", "owner"}, nil, true},
+		{"field key with colon", []string{"--field", "a:b=c"}, nil, true},
+		{"tag with comma", []string{"--tag", "prod,eu"}, nil, true},
+		{"tag with space", []string{"--tag", "prod eu"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			sf := addServerFlags(cmd)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			got := base
+			err := sf.apply(cmd.Flags(), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			want := base
+			tt.want(&want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("apply() =\n%+v\nwant\n%+v", got, want)
+			}
+		})
+	}
+}
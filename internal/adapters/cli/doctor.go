@@ -0,0 +1,76 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/spf13/cobra"
+)
+
+// NewDoctorCommand returns `lazyssh doctor`, which checks the SSH config for common mistakes.
+func NewDoctorCommand(ss ports.ServerService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the SSH config for common mistakes",
+		Long: `Check every server in the SSH config and its includes for missing or world-readable
+identity files, aliases defined twice, ProxyJump hosts that are unknown or loop, deprecated
+options, ControlMaster without a usable ControlPath and values the server form would reject.
+Problems are printed as file:line: severity: alias: message. The exit status is nonzero if
+any of them is an error.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			findings, err := ss.Lint()
+			if err != nil {
+				return fmt.Errorf("check servers: %w", err)
+			}
+			return runDoctor(cmd.OutOrStdout(), findings)
+		},
+	}
+}
+
+// runDoctor prints findings and a summary, and fails if any finding is an error.
+func runDoctor(w io.Writer, findings []domain.Finding) error {
+	errorCount := 0
+	for _, f := range findings {
+		if f.Severity == domain.SeverityError {
+			errorCount++
+		}
+		if _, err := fmt.Fprintf(w, "%s:%d: %s: %s: %s\n", f.File, f.Line, f.Severity, f.Alias, f.Message); err != nil {
+			return err
+		}
+	}
+
+	summary := fmt.Sprintf("%s, %s", plural(errorCount, "error"), plural(len(findings)-errorCount, "warning"))
+	if errorCount > 0 {
+		return errors.New(summary)
+	}
+	if len(findings) == 0 {
+		summary = "no problems found"
+	}
+	_, err := fmt.Fprintln(w, summary)
+	return err
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,53 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestRunDoctor(t *testing.T) {
+	warning := domain.Finding{Severity: domain.SeverityWarning, Alias: "db", Message: "deprecated", File: "config", Line: 3}
+	failure := domain.Finding{Severity: domain.SeverityError, Alias: "web", Message: "missing key", File: "config", Line: 7}
+
+	tests := []struct {
+		name     string
+		findings []domain.Finding
+		want     string
+		wantErr  string
+	}{
+		{"clean", nil, "no problems found\n", ""},
+		{"warnings only", []domain.Finding{warning}, "config:3: warning: db: deprecated\n0 errors, 1 warning\n", ""},
+		{"errors", []domain.Finding{warning, failure}, "config:3: warning: db: deprecated\nconfig:7: error: web: missing key\n", "1 error, 1 warning"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runDoctor(&out, tt.findings)
+			if got := out.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/Adembc/lazyssh/internal/core/services"
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	format  string
+	tags    []string
+	sort    string
+	reverse bool
+}
+
+// NewListCommand returns `lazyssh list [query]`, which prints the servers matching a search.
+func NewListCommand(ss ports.ServerService) *cobra.Command {
+	var opts listOptions
+	cmd := &cobra.Command{
+		Use:   "list [query]",
+		Short: "Print servers, optionally filtered by a search query",
+		Long: `Print servers, filtered with the same query syntax as the search bar, e.g.
+'lazyssh list web tag:prod -tag:legacy'. Without a query servers are sorted like the
+server list; with one they are ranked by how well they match unless --sort is given.`,
+		Example: `  lazyssh list --tag prod --format json | jq -r '.[].host'
+  lazyssh list --sort frecency --format '{{.Alias}}' | fzf`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(cmd.OutOrStdout(), ss, strings.Join(args, " "), opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.format, "format", "o", "table", "output format: table, json, yaml or a Go template such as '{{.Alias}} {{.Host}}'")
+	cmd.Flags().StringArrayVarP(&opts.tags, "tag", "t", nil, "only list servers with this tag (repeatable)")
+	cmd.Flags().StringVar(&opts.sort, "sort", "", "sort by "+strings.Join(services.SortFieldKeys(), ", "))
+	cmd.Flags().BoolVar(&opts.reverse, "reverse", false, "reverse the sort order")
+	registerTagCompletion(cmd, ss, "tag")
+	return cmd
+}
+
+func runList(w io.Writer, ss ports.ServerService, query string, opts listOptions) error {
+	mode := services.SortMode{}
+	if opts.sort != "" {
+		var ok bool
+		if mode, ok = services.ParseSortMode(opts.sort); !ok {
+			return fmt.Errorf("unknown sort field %q: use one of %s", opts.sort, strings.Join(services.SortFieldKeys(), ", "))
+		}
+	}
+	if opts.reverse {
+		mode = mode.Reverse()
+	}
+
+	terms := make([]string, 0, len(opts.tags)+1)
+	for _, tag := range opts.tags {
+		terms = append(terms, "tag:"+tag)
+	}
+	if query = strings.TrimSpace(query); query != "" {
+		terms = append(terms, query)
+	}
+	servers, err := ss.ListServers(strings.Join(terms, " "))
+	if err != nil {
+		return fmt.Errorf("list servers: %w", err)
+	}
+	// A query ranks servers by relevance; keep that order unless a sort was asked for.
+	if query == "" || opts.sort != "" || opts.reverse {
+		services.SortServers(servers, mode)
+	}
+	return writeServers(w, servers, opts.format)
+}
@@ -0,0 +1,106 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+)
+
+// fakeService serves a fixed list of servers; the query is recorded, not applied.
+type fakeService struct {
+	ports.ServerService
+	servers []domain.Server
+	query   string
+}
+
+func (f *fakeService) ListServers(query string) ([]domain.Server, error) {
+	f.query = query
+	return append([]domain.Server(nil), f.servers...), nil
+}
+
+func TestRunList(t *testing.T) {
+	seen := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	servers := []domain.Server{
+		{Alias: "web", Host: "10.0.0.2", User: "deploy", Port: 22, Tags: []string{"prod", "web"}, LastSeen: seen, SSHCount: 4},
+		{Alias: "db", Host: "10.0.0.10", SourceFile: "/home/u/.ssh/config"},
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		opts      listOptions
+		wantQuery string
+		want      string
+	}{
+		{
+			name: "template sorted by alias",
+			opts: listOptions{format: "{{.Alias}} {{join .Tags \",\"}}"},
+			want: "db \nweb prod,web\n",
+		},
+		{
+			name:      "query and tags keep the ranking",
+			query:     "10.0",
+			opts:      listOptions{format: "{{.Alias}}", tags: []string{"prod"}},
+			wantQuery: "tag:prod 10.0",
+			want:      "web\ndb\n",
+		},
+		{
+			name: "sort by host, reversed",
+			opts: listOptions{format: "{{.Host}}", sort: "host", reverse: true},
+			want: "10.0.0.10\n10.0.0.2\n",
+		},
+		{
+			name: "json",
+			opts: listOptions{format: "json", sort: "ssh_count"},
+			want: `"alias": "web",`,
+		},
+		{
+			name: "yaml",
+			opts: listOptions{format: "yaml"},
+			want: "- alias: db\n  aliases:\n    - db\n  host: 10.0.0.10\n  tags: []\n  pinned: false\n  ssh_count: 0\n  source_file: /home/u/.ssh/config\n",
+		},
+		{
+			name: "table",
+			opts: listOptions{format: "table"},
+			want: "ALIAS  HOST       USER    PORT  TAGS      LAST SSH\ndb     10.0.0.10                          never\n",
+		},
+	}
+	for _, tt := range tests {
+		ss := &fakeService{servers: servers}
+		var out bytes.Buffer
+		if err := runList(&out, ss, tt.query, tt.opts); err != nil {
+			t.Errorf("%s: runList() error = %v", tt.name, err)
+			continue
+		}
+		if ss.query != tt.wantQuery {
+			t.Errorf("%s: ListServers(%q), want %q", tt.name, ss.query, tt.wantQuery)
+		}
+		if !strings.Contains(out.String(), tt.want) {
+			t.Errorf("%s: output = %q, want it to contain %q", tt.name, out.String(), tt.want)
+		}
+	}
+
+	for _, opts := range []listOptions{{format: "xml"}, {sort: "color"}, {format: "{{.Nope}}"}} {
+		if err := runList(&bytes.Buffer{}, &fakeService{servers: servers}, "", opts); err == nil {
+			t.Errorf("runList(%+v) succeeded, want error", opts)
+		}
+	}
+}
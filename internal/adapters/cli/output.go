@@ -0,0 +1,153 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cli implements the non-interactive lazyssh subcommands, for use in scripts.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// serverRecord is how a server is printed as JSON or YAML.
+type serverRecord struct {
+	Alias         string            `json:"alias" yaml:"alias"`
+	Aliases       []string          `json:"aliases" yaml:"aliases"`
+	Host          string            `json:"host" yaml:"host"`
+	User          string            `json:"user,omitempty" yaml:"user,omitempty"`
+	Port          int               `json:"port,omitempty" yaml:"port,omitempty"`
+	ProxyJump     string            `json:"proxy_jump,omitempty" yaml:"proxy_jump,omitempty"`
+	IdentityFiles []string          `json:"identity_files,omitempty" yaml:"identity_files,omitempty"`
+	Tags          []string          `json:"tags" yaml:"tags"`
+	Pinned        bool              `json:"pinned" yaml:"pinned"`
+	LastSeen      *time.Time        `json:"last_seen,omitempty" yaml:"last_seen,omitempty"`
+	SSHCount      int               `json:"ssh_count" yaml:"ssh_count"`
+	Notes         string            `json:"notes,omitempty" yaml:"notes,omitempty"`
+	Fields        map[string]string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	SourceFile    string            `json:"source_file" yaml:"source_file"`
+}
+
+func newServerRecord(s domain.Server) serverRecord {
+	record := serverRecord{
+		Alias:         s.Alias,
+		Aliases:       s.Aliases,
+		Host:          s.Host,
+		User:          s.User,
+		Port:          s.Port,
+		ProxyJump:     s.ProxyJump,
+		IdentityFiles: s.IdentityFiles,
+		Tags:          s.Tags,
+		Pinned:        !s.PinnedAt.IsZero(),
+		SSHCount:      s.SSHCount,
+		Notes:         s.Notes,
+		Fields:        s.Fields,
+		SourceFile:    s.SourceFile,
+	}
+	if record.Aliases == nil {
+		record.Aliases = []string{s.Alias}
+	}
+	if record.Tags == nil {
+		record.Tags = []string{}
+	}
+	if !s.LastSeen.IsZero() {
+		lastSeen := s.LastSeen
+		record.LastSeen = &lastSeen
+	}
+	return record
+}
+
+// writeServers prints servers as a table, as JSON or YAML, or through a Go template that is
+// executed once per server, e.g. '{{.Alias}} {{.Host}}'.
+func writeServers(w io.Writer, servers []domain.Server, format string) error {
+	switch format {
+	case "", "table":
+		return writeTable(w, servers)
+	case "json", "yaml":
+		records := make([]serverRecord, len(servers))
+		for i := range servers {
+			records[i] = newServerRecord(servers[i])
+		}
+		return writeStructured(w, records, format)
+	}
+	if !strings.Contains(format, "{{") {
+		return fmt.Errorf("unknown format %q: use table, json, yaml or a Go template", format)
+	}
+	return writeTemplate(w, servers, format)
+}
+
+// writeStructured prints v as indented JSON or as YAML.
+func writeStructured(w io.Writer, v any, format string) error {
+	if format == "yaml" {
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("encode yaml: %w", err)
+		}
+		return enc.Close()
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+	return nil
+}
+
+func writeTable(w io.Writer, servers []domain.Server) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ALIAS\tHOST\tUSER\tPORT\tTAGS\tLAST SSH")
+	for _, s := range servers {
+		port := ""
+		if s.Port != 0 {
+			port = strconv.Itoa(s.Port)
+		}
+		lastSeen := "never"
+		if !s.LastSeen.IsZero() {
+			lastSeen = s.LastSeen.Local().Format("2006-01-02 15:04")
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.Alias, s.Host, s.User, port, strings.Join(s.Tags, ","), lastSeen)
+	}
+	return tw.Flush()
+}
+
+// templateFuncs are available in --format templates besides the builtin ones.
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+func writeTemplate(w io.Writer, servers []domain.Server, format string) error {
+	if !strings.HasSuffix(format, "\n") {
+		format += "\n"
+	}
+	tmpl, err := template.New("format").Funcs(templateFuncs).Parse(format)
+	if err != nil {
+		return fmt.Errorf("parse format: %w", err)
+	}
+	for _, s := range servers {
+		if err := tmpl.Execute(w, s); err != nil {
+			return fmt.Errorf("format %s: %w", s.Alias, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// pickerRows is how many matches the picker shows at once.
+const pickerRows = 10
+
+// errPickerCanceled is returned when the user leaves the picker without choosing a server.
+var errPickerCanceled = errors.New("canceled")
+
+// picker is a small fuzzy finder drawn below the cursor: the query on the first line and
+// the best matches under it. It only keeps state; terminal setup is left to the caller.
+type picker struct {
+	search   func(query string) ([]domain.SearchResult, error)
+	query    []rune
+	results  []domain.SearchResult
+	selected int
+}
+
+func newPicker(query string, search func(string) ([]domain.SearchResult, error)) (*picker, error) {
+	p := &picker{search: search, query: []rune(query)}
+	return p, p.update()
+}
+
+func (p *picker) update() error {
+	results, err := p.search(string(p.query))
+	if err != nil {
+		return err
+	}
+	p.results = results
+	p.selected = 0
+	return nil
+}
+
+// handleKey applies one key press, as read from a terminal in raw mode, and returns the
+// chosen server once Enter is pressed.
+func (p *picker) handleKey(key []byte) (*domain.Server, error) {
+	switch string(key) {
+	case "\r", "\n":
+		if len(p.results) == 0 {
+			return nil, nil
+		}
+		return &p.results[p.selected].Server, nil
+	case "\x1b", "\x03", "\x07": // Esc, Ctrl+C, Ctrl+G
+		return nil, errPickerCanceled
+	case "\x1b[A", "\x1bOA", "\x10": // Up, Ctrl+P
+		if p.selected > 0 {
+			p.selected--
+		}
+		return nil, nil
+	case "\x1b[B", "\x1bOB", "\x0e": // Down, Ctrl+N
+		if p.selected < min(len(p.results), pickerRows)-1 {
+			p.selected++
+		}
+		return nil, nil
+	case "\x7f", "\x08": // Backspace
+		if len(p.query) == 0 {
+			return nil, nil
+		}
+		p.query = p.query[:len(p.query)-1]
+		return nil, p.update()
+	case "\x15": // Ctrl+U
+		p.query = p.query[:0]
+		return nil, p.update()
+	}
+
+	changed := false
+	for len(key) > 0 {
+		r, size := utf8.DecodeRune(key)
+		key = key[size:]
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
+			return nil, nil // an escape sequence the picker doesn't use
+		}
+		p.query = append(p.query, r)
+		changed = true
+	}
+	if changed {
+		return nil, p.update()
+	}
+	return nil, nil
+}
+
+// render redraws the picker over its previous drawing, which starts on the cursor line.
+// Lines end in \r\n since the terminal is in raw mode.
+func (p *picker) render(w io.Writer) {
+	var b strings.Builder
+	b.WriteString("\r\x1b[J")
+	fmt.Fprintf(&b, "\x1b[1mconnect>\x1b[0m %s", string(p.query))
+
+	rows := 0
+	for i, result := range p.results {
+		if i == pickerRows {
+			fmt.Fprintf(&b, "\r\n  \x1b[2m… %d more\x1b[0m", len(p.results)-pickerRows)
+			rows++
+			break
+		}
+		line := fmt.Sprintf("%s  \x1b[2m%s\x1b[0m", boldMatches(result.Server.Alias, result.AliasMatches), destination(result.Server))
+		if i == p.selected {
+			fmt.Fprintf(&b, "\r\n\x1b[7m>\x1b[0m %s", line)
+		} else {
+			fmt.Fprintf(&b, "\r\n  %s", line)
+		}
+		rows++
+	}
+	if len(p.results) == 0 {
+		b.WriteString("\r\n  \x1b[2mno matches\x1b[0m")
+		rows++
+	}
+	// Park the cursor at the end of the query line.
+	fmt.Fprintf(&b, "\x1b[%dA\r\x1b[%dC", rows, utf8.RuneCountInString("connect> ")+len(p.query))
+	_, _ = io.WriteString(w, b.String())
+}
+
+// clear erases the picker, leaving the cursor where it started.
+func (p *picker) clear(w io.Writer) {
+	_, _ = io.WriteString(w, "\r\x1b[J")
+}
+
+// boldMatches makes the runes of text at the given offsets bold.
+func boldMatches(text string, positions []int) string {
+	matched := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		matched[pos] = true
+	}
+	var b strings.Builder
+	i := 0
+	for _, r := range text {
+		if matched[i] {
+			b.WriteString("\x1b[1;33m" + string(r) + "\x1b[0m")
+		} else {
+			b.WriteRune(r)
+		}
+		i++
+	}
+	return b.String()
+}
+
+// destination describes where a server connects to, e.g. root@10.0.0.1:2222.
+func destination(s domain.Server) string {
+	dest := s.Host
+	if s.User != "" {
+		dest = s.User + "@" + dest
+	}
+	if s.Port != 0 && s.Port != 22 {
+		dest = fmt.Sprintf("%s:%d", dest, s.Port)
+	}
+	return dest
+}
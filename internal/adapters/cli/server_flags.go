@@ -0,0 +1,250 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/services"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// optionFlag maps a command line flag to an SSH option field of domain.Server, named as
+// the field. String fields take one value; slice fields repeat the flag once per value.
+// The field name is also the name of the UI validator of the option, where there is one.
+type optionFlag struct {
+	name  string
+	field string
+}
+
+// optionFlags are the SSH options that can be set from the command line besides the basic
+// connection settings, in the order of domain.Server.
+var optionFlags = []optionFlag{
+	// Connection and proxy settings
+	{"proxy-jump", "ProxyJump"},
+	{"proxy-command", "ProxyCommand"},
+	{"remote-command", "RemoteCommand"},
+	{"request-tty", "RequestTTY"},
+	{"session-type", "SessionType"},
+	{"connect-timeout", "ConnectTimeout"},
+	{"connection-attempts", "ConnectionAttempts"},
+	{"bind-address", "BindAddress"},
+	{"bind-interface", "BindInterface"},
+	{"address-family", "AddressFamily"},
+	{"exit-on-forward-failure", "ExitOnForwardFailure"},
+	{"ipqos", "IPQoS"},
+	// Hostname canonicalization
+	{"canonicalize-hostname", "CanonicalizeHostname"},
+	{"canonical-domains", "CanonicalDomains"},
+	{"canonicalize-fallback-local", "CanonicalizeFallbackLocal"},
+	{"canonicalize-max-dots", "CanonicalizeMaxDots"},
+	{"canonicalize-permitted-cnames", "CanonicalizePermittedCNAMEs"},
+	// Port forwarding settings
+	{"local-forward", "LocalForward"},
+	{"remote-forward", "RemoteForward"},
+	{"dynamic-forward", "DynamicForward"},
+	{"clear-all-forwardings", "ClearAllForwardings"},
+	{"gateway-ports", "GatewayPorts"},
+	// Authentication and key management
+	{"pubkey-authentication", "PubkeyAuthentication"},
+	{"pubkey-accepted-algorithms", "PubkeyAcceptedAlgorithms"},
+	{"hostbased-accepted-algorithms", "HostbasedAcceptedAlgorithms"},
+	{"identities-only", "IdentitiesOnly"},
+	{"add-keys-to-agent", "AddKeysToAgent"},
+	{"identity-agent", "IdentityAgent"},
+	{"password-authentication", "PasswordAuthentication"},
+	{"kbd-interactive-authentication", "KbdInteractiveAuthentication"},
+	{"number-of-password-prompts", "NumberOfPasswordPrompts"},
+	{"preferred-authentications", "PreferredAuthentications"},
+	// Agent and X11 forwarding
+	{"forward-agent", "ForwardAgent"},
+	{"forward-x11", "ForwardX11"},
+	{"forward-x11-trusted", "ForwardX11Trusted"},
+	// Connection multiplexing
+	{"control-master", "ControlMaster"},
+	{"control-path", "ControlPath"},
+	{"control-persist", "ControlPersist"},
+	// Connection reliability settings
+	{"server-alive-interval", "ServerAliveInterval"},
+	{"server-alive-count-max", "ServerAliveCountMax"},
+	{"compression", "Compression"},
+	{"tcp-keep-alive", "TCPKeepAlive"},
+	{"batch-mode", "BatchMode"},
+	// Security and cryptography settings
+	{"strict-host-key-checking", "StrictHostKeyChecking"},
+	{"check-host-ip", "CheckHostIP"},
+	{"fingerprint-hash", "FingerprintHash"},
+	{"user-known-hosts-file", "UserKnownHostsFile"},
+	{"host-key-algorithms", "HostKeyAlgorithms"},
+	{"macs", "MACs"},
+	{"ciphers", "Ciphers"},
+	{"kex-algorithms", "KexAlgorithms"},
+	{"verify-host-key-dns", "VerifyHostKeyDNS"},
+	{"update-host-keys", "UpdateHostKeys"},
+	{"hash-known-hosts", "HashKnownHosts"},
+	{"visual-host-key", "VisualHostKey"},
+	// Command execution
+	{"local-command", "LocalCommand"},
+	{"permit-local-command", "PermitLocalCommand"},
+	{"escape-char", "EscapeChar"},
+	// Environment settings
+	{"send-env", "SendEnv"},
+	{"set-env", "SetEnv"},
+	// Debugging settings
+	{"log-level", "LogLevel"},
+}
+
+// serverFlags are the values of the flags shared by add and edit.
+type serverFlags struct {
+	host          string
+	user          string
+	port          int
+	identityFiles []string
+	tags          []string
+	notes         string
+	fields        []string
+	options       map[string]*string
+	listOptions   map[string]*[]string
+}
+
+// addServerFlags registers the server setting flags on cmd.
+func addServerFlags(cmd *cobra.Command) *serverFlags {
+	sf := &serverFlags{options: make(map[string]*string), listOptions: make(map[string]*[]string)}
+	flags := cmd.Flags()
+	flags.StringVar(&sf.host, "host", "", "HostName: host name or IP address to connect to")
+	flags.StringVar(&sf.user, "user", "", "User to log in as")
+	flags.IntVar(&sf.port, "port", 22, "Port to connect to")
+	flags.StringArrayVar(&sf.identityFiles, "identity-file", nil, "IdentityFile: private key file (repeatable)")
+	flags.StringArrayVar(&sf.tags, "tag", nil, "tag (repeatable)")
+	flags.StringVar(&sf.notes, "notes", "", "free-form Markdown notes")
+	flags.StringArrayVar(&sf.fields, "field", nil, "custom field as key=value; an empty value removes the field (repeatable)")
+
+	serverType := reflect.TypeOf(domain.Server{})
+	for _, option := range optionFlags {
+		field, _ := serverType.FieldByName(option.field)
+		if field.Type.Kind() == reflect.Slice {
+			sf.listOptions[option.name] = flags.StringArray(option.name, nil, option.field+" (repeatable)")
+		} else {
+			sf.options[option.name] = flags.String(option.name, "", option.field)
+		}
+	}
+	return sf
+}
+
+// apply sets the settings whose flags were given on server, validating each value with the
+// rules of the server form. An empty value removes a setting.
+func (sf *serverFlags) apply(flags *pflag.FlagSet, server *domain.Server) error {
+	validate := func(flag, field string, values ...string) error {
+		for _, value := range values {
+			if err := services.ValidateField(field, value); err != nil {
+				return fmt.Errorf("--%s: %w", flag, err)
+			}
+		}
+		return nil
+	}
+
+	if flags.Changed("host") {
+		if err := validate("host", "Host", sf.host); err != nil {
+			return err
+		}
+		server.Host = sf.host
+	}
+	if flags.Changed("user") {
+		if err := validate("user", "User", sf.user); err != nil {
+			return err
+		}
+		server.User = sf.user
+	}
+	if flags.Changed("port") {
+		if err := validate("port", "Port", fmt.Sprint(sf.port)); err != nil {
+			return err
+		}
+		server.Port = sf.port
+	}
+	if flags.Changed("identity-file") {
+		server.IdentityFiles = nonEmpty(sf.identityFiles)
+		if err := validate("identity-file", "Keys", server.IdentityFiles...); err != nil {
+			return err
+		}
+	}
+	if flags.Changed("tag") {
+		server.Tags = nonEmpty(sf.tags)
+		if err := validate("tag", "Tag", server.Tags...); err != nil {
+			return err
+		}
+	}
+	if flags.Changed("notes") {
+		server.Notes = sf.notes
+	}
+	for _, field := range sf.fields {
+		key, value, ok := strings.Cut(field, "=")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			return fmt.Errorf("--field: expected key=value, got %q", field)
+		}
+		if err := validate("field", "FieldKey", key); err != nil {
+			return err
+		}
+		if err := validate("field", "Fields", key+": "+value); err != nil {
+			return err
+		}
+		fields := make(map[string]string, len(server.Fields)+1)
+		for k, v := range server.Fields {
+			fields[k] = v
+		}
+		if value = strings.TrimSpace(value); value == "" {
+			delete(fields, key)
+		} else {
+			fields[key] = value
+		}
+		server.Fields = fields
+	}
+
+	target := reflect.ValueOf(server).Elem()
+	for _, option := range optionFlags {
+		if !flags.Changed(option.name) {
+			continue
+		}
+		field := target.FieldByName(option.field)
+		if values, ok := sf.listOptions[option.name]; ok {
+			list := nonEmpty(*values)
+			if err := validate(option.name, option.field, list...); err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(list))
+			continue
+		}
+		value := strings.TrimSpace(*sf.options[option.name])
+		if err := validate(option.name, option.field, value); err != nil {
+			return err
+		}
+		field.SetString(value)
+	}
+	return nil
+}
+
+// nonEmpty returns the trimmed non-empty values, or nil if there are none.
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
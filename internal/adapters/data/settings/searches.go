@@ -0,0 +1,169 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/fsutil"
+	"gopkg.in/yaml.v3"
+)
+
+const searchesKey = "searches"
+
+// SearchStore keeps saved searches in the searches list of the settings file. Saving and
+// deleting edit only that list, so the rest of the file, comments included, is kept as written.
+type SearchStore struct {
+	path string
+}
+
+// NewSearchStore returns a store for the saved searches of the settings file at path.
+func NewSearchStore(path string) *SearchStore {
+	return &SearchStore{path: path}
+}
+
+// ListSavedSearches returns the saved searches in file order.
+func (s *SearchStore) ListSavedSearches() ([]domain.SavedSearch, error) {
+	settings, err := Load(s.path)
+	if err != nil {
+		return nil, err
+	}
+	searches := make([]domain.SavedSearch, len(settings.Searches))
+	for i, search := range settings.Searches {
+		searches[i] = domain.SavedSearch{Name: search.Name, Query: search.Query}
+	}
+	return searches, nil
+}
+
+// SaveSearch appends search to the list, or replaces the query of the search with the same name.
+func (s *SearchStore) SaveSearch(search domain.SavedSearch) error {
+	return s.update(func(list *yaml.Node) error {
+		for _, item := range list.Content {
+			if name := mappingValue(item, "name"); name != nil && name.Value == search.Name {
+				if query := mappingValue(item, "query"); query != nil {
+					query.SetString(search.Query)
+					return nil
+				}
+				item.Content = append(item.Content, stringNode("query"), stringNode(search.Query))
+				return nil
+			}
+		}
+		list.Style = 0
+		list.Content = append(list.Content, &yaml.Node{
+			Kind:    yaml.MappingNode,
+			Tag:     "!!map",
+			Content: []*yaml.Node{stringNode("name"), stringNode(search.Name), stringNode("query"), stringNode(search.Query)},
+		})
+		return nil
+	})
+}
+
+// DeleteSavedSearch removes the saved search with the given name.
+func (s *SearchStore) DeleteSavedSearch(name string) error {
+	return s.update(func(list *yaml.Node) error {
+		for i, item := range list.Content {
+			if n := mappingValue(item, "name"); n != nil && n.Value == name {
+				list.Content = append(list.Content[:i], list.Content[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("no saved search named %q", name)
+	})
+}
+
+// update applies change to the searches list of the settings file, creating the file or the
+// list when missing, and writes the file back if the result is valid.
+func (s *SearchStore) update(change func(list *yaml.Node) error) error {
+	// #nosec G304 -- the settings path is fixed by lazyssh, not user-supplied
+	data, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read settings '%s': %w", s.path, err)
+	}
+
+	var doc yaml.Node
+	if strings.TrimSpace(string(data)) != "" {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("parse settings '%s': %w", s.path, err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("settings '%s' is not a mapping", s.path)
+	}
+
+	list := mappingValue(root, searchesKey)
+	if list == nil {
+		list = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		root.Content = append(root.Content, stringNode(searchesKey), list)
+	}
+	if list.Kind == yaml.ScalarNode && list.Tag == "!!null" {
+		// "searches:" without items
+		list.Kind, list.Tag, list.Value = yaml.SequenceNode, "!!seq", ""
+	}
+	if list.Kind != yaml.SequenceNode {
+		return fmt.Errorf("settings '%s': %s must be a list", s.path, searchesKey)
+	}
+	if err := change(list); err != nil {
+		return err
+	}
+
+	var out strings.Builder
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("encode settings: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("encode settings: %w", err)
+	}
+
+	updated := Default()
+	if err := yaml.Unmarshal([]byte(out.String()), &updated); err != nil {
+		return fmt.Errorf("encode settings: %w", err)
+	}
+	if err := updated.validate(); err != nil {
+		return err
+	}
+	if err := fsutil.WriteFileAtomic(s.path, []byte(out.String())); err != nil {
+		return fmt.Errorf("write settings: %w", err)
+	}
+	return nil
+}
+
+// mappingValue returns the value of key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func stringNode(value string) *yaml.Node {
+	node := &yaml.Node{}
+	node.SetString(value)
+	return node
+}
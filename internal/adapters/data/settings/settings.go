@@ -0,0 +1,166 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package settings reads lazyssh's own settings file, ~/.lazyssh/config.yaml.
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/fsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultMaxBackups is the number of rolling backups kept per config file when not configured.
+const DefaultMaxBackups = 10
+
+// Where per-server metadata (tags, pins, notes and custom fields) is stored.
+const (
+	// MetadataStoreFile keeps metadata in lazyssh's metadata file, keyed by alias.
+	MetadataStoreFile = "file"
+	// MetadataStoreConfig keeps metadata as "# lazyssh:" comments inside each Host block,
+	// so it travels with the SSH config. Connection history stays in the metadata file.
+	MetadataStoreConfig = "config"
+)
+
+// Settings are the user-configurable options of lazyssh.
+type Settings struct {
+	Backup   Backup        `yaml:"backup"`
+	Metadata Metadata      `yaml:"metadata"`
+	Searches []SavedSearch `yaml:"searches"`
+}
+
+// Backup controls where backups of the SSH config are written and how many are kept.
+type Backup struct {
+	// Dir is where backups are written. Empty keeps them beside the main config, and those
+	// of included files in a backups directory beside the metadata file.
+	Dir string `yaml:"dir"`
+	// MaxCount is the number of rolling backups kept per config file; 0 disables the limit.
+	MaxCount int `yaml:"max_count"`
+	// MaxAge removes rolling backups older than this; 0 disables the limit.
+	MaxAge Duration `yaml:"max_age"`
+	// Compress gzips rolling backups.
+	Compress bool `yaml:"compress"`
+}
+
+// Metadata controls where per-server metadata is stored.
+type Metadata struct {
+	// Store is MetadataStoreFile or MetadataStoreConfig.
+	Store string `yaml:"store"`
+}
+
+// SavedSearch is a search query saved under a name, shown as a group in the server list.
+type SavedSearch struct {
+	Name  string `yaml:"name"`
+	Query string `yaml:"query"`
+}
+
+// Duration is a time.Duration that also accepts a number of days, e.g. "30d".
+type Duration time.Duration
+
+// UnmarshalYAML parses values such as "72h", "30d" or "0".
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := parseDuration(strings.TrimSpace(value.Value))
+	if err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return parsed, nil
+}
+
+// Default returns the settings used when no settings file exists.
+func Default() Settings {
+	return Settings{
+		Backup:   Backup{MaxCount: DefaultMaxBackups},
+		Metadata: Metadata{Store: MetadataStoreFile},
+	}
+}
+
+// Load reads the settings file at path. Options missing from the file keep their defaults,
+// and a missing file yields the defaults. A relative backup directory is resolved against
+// the directory of the settings file.
+func Load(path string) (Settings, error) {
+	s := Default()
+
+	// #nosec G304 -- the settings path is fixed by lazyssh, not user-supplied
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("read settings '%s': %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return Default(), fmt.Errorf("parse settings '%s': %w", path, err)
+	}
+	if err := s.validate(); err != nil {
+		return Default(), fmt.Errorf("invalid settings '%s': %w", path, err)
+	}
+
+	if s.Backup.Dir != "" {
+		s.Backup.Dir = fsutil.ExpandHome(s.Backup.Dir)
+		if !filepath.IsAbs(s.Backup.Dir) {
+			s.Backup.Dir = filepath.Join(filepath.Dir(path), s.Backup.Dir)
+		}
+	}
+	return s, nil
+}
+
+func (s Settings) validate() error {
+	if s.Backup.MaxCount < 0 {
+		return fmt.Errorf("backup.max_count must not be negative, got %d", s.Backup.MaxCount)
+	}
+	if s.Backup.MaxAge < 0 {
+		return fmt.Errorf("backup.max_age must not be negative, got %s", time.Duration(s.Backup.MaxAge))
+	}
+	if s.Metadata.Store != MetadataStoreFile && s.Metadata.Store != MetadataStoreConfig {
+		return fmt.Errorf("metadata.store must be %q or %q, got %q", MetadataStoreFile, MetadataStoreConfig, s.Metadata.Store)
+	}
+	names := make(map[string]bool, len(s.Searches))
+	for i, search := range s.Searches {
+		if strings.TrimSpace(search.Name) == "" || strings.TrimSpace(search.Query) == "" {
+			return fmt.Errorf("searches[%d] needs both a name and a query", i)
+		}
+		if names[search.Name] {
+			return fmt.Errorf("searches: duplicate name %q", search.Name)
+		}
+		names[search.Name] = true
+	}
+	return nil
+}
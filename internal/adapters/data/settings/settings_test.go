@@ -0,0 +1,133 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Backup
+		wantErr bool
+	}{
+		{"empty", "", Backup{MaxCount: DefaultMaxBackups}, false},
+		{
+			"all options",
+			"backup:\n  dir: backups\n  max_count: 50\n  max_age: 30d\n  compress: true\n",
+			Backup{Dir: "backups", MaxCount: 50, MaxAge: Duration(30 * 24 * time.Hour), Compress: true},
+			false,
+		},
+		{"partial", "backup:\n  max_age: 12h\n", Backup{MaxCount: DefaultMaxBackups, MaxAge: Duration(12 * time.Hour)}, false},
+		{"count limit disabled", "backup:\n  max_count: 0\n", Backup{}, false},
+		{"negative count", "backup:\n  max_count: -1\n", Backup{}, true},
+		{"bad duration", "backup:\n  max_age: soon\n", Backup{}, true},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.yaml")
+		if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Load(path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Load() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if tt.want.Dir != "" {
+			tt.want.Dir = filepath.Join(dir, tt.want.Dir)
+		}
+		if got.Backup != tt.want {
+			t.Errorf("%s: Load() = %+v, want %+v", tt.name, got.Backup, tt.want)
+		}
+	}
+
+	for content, want := range map[string]string{
+		"":                             MetadataStoreFile,
+		"metadata:\n  store: config\n": MetadataStoreConfig,
+		"metadata:\n  store: cloud\n":  "",
+	} {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Load(path)
+		if want == "" {
+			if err == nil {
+				t.Errorf("Load(%q) succeeded, want an error for the unknown store", content)
+			}
+			continue
+		}
+		if err != nil || got.Metadata.Store != want {
+			t.Errorf("Load(%q) metadata store = %q, %v, want %q", content, got.Metadata.Store, err, want)
+		}
+	}
+
+	got, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil || !reflect.DeepEqual(got, Default()) {
+		t.Errorf("Load() of a missing file = %+v, %v, want defaults", got, err)
+	}
+}
+
+func TestSearchStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "# my settings\nbackup:\n  max_count: 5 # keep a few\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store := NewSearchStore(path)
+
+	searches := []domain.SavedSearch{
+		{Name: "Prod DBs", Query: "tag:prod tag:db"},
+		{Name: "Legacy", Query: "tag:legacy"},
+		{Name: "Prod DBs", Query: "tag:prod -tag:legacy"},
+	}
+	for _, search := range searches {
+		if err := store.SaveSearch(search); err != nil {
+			t.Fatalf("SaveSearch(%+v) error = %v", search, err)
+		}
+	}
+	if err := store.DeleteSavedSearch("Legacy"); err != nil {
+		t.Fatalf("DeleteSavedSearch() error = %v", err)
+	}
+	if err := store.DeleteSavedSearch("Legacy"); err == nil {
+		t.Errorf("DeleteSavedSearch() of a missing search succeeded, want error")
+	}
+
+	got, err := store.ListSavedSearches()
+	want := []domain.SavedSearch{{Name: "Prod DBs", Query: "tag:prod -tag:legacy"}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ListSavedSearches() = %+v, %v, want %+v", got, err, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# my settings") || !strings.Contains(string(data), "max_count: 5 # keep a few") {
+		t.Errorf("settings file lost its other content:\n%s", data)
+	}
+}
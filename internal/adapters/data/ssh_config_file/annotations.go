@@ -0,0 +1,203 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/adapters/data/settings"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/kevinburke/ssh_config"
+)
+
+// annotationPrefix starts the comments in which lazyssh keeps the metadata of a Host block
+// when settings.MetadataStoreConfig is selected:
+//
+//	Host web
+//	    # lazyssh:tags=prod,db
+//	    # lazyssh:pinned=2025-01-02T15:04:05Z
+//	    # lazyssh:field.owner=alice
+//	    # lazyssh:note=Restart with `systemctl restart nginx`
+//	    HostName 10.0.0.1
+//
+// Notes take one comment per line. Connection history is machine-local and stays in the
+// metadata file.
+const annotationPrefix = "lazyssh:"
+
+// annotationsInConfig reports whether tags, pins, notes and fields are stored in the SSH config.
+func (r *Repository) annotationsInConfig() bool {
+	return r.metadataStore == settings.MetadataStoreConfig
+}
+
+// readAnnotations applies the lazyssh comments of host to server.
+func readAnnotations(host *ssh_config.Host, server *domain.Server) {
+	var notes []string
+	for _, node := range host.Nodes {
+		key, value, ok := parseAnnotation(node)
+		if !ok {
+			continue
+		}
+		switch {
+		case key == "tags":
+			server.Tags = nil
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					server.Tags = append(server.Tags, tag)
+				}
+			}
+		case key == "pinned":
+			if pinnedAt, err := time.Parse(time.RFC3339, value); err == nil {
+				server.PinnedAt = pinnedAt
+			}
+		case key == "note":
+			notes = append(notes, value)
+		case strings.HasPrefix(key, "field."):
+			if server.Fields == nil {
+				server.Fields = make(map[string]string)
+			}
+			server.Fields[strings.TrimPrefix(key, "field.")] = value
+		}
+	}
+	if notes != nil {
+		server.Notes = strings.Join(notes, "\n")
+	}
+}
+
+// writeAnnotations replaces the lazyssh comments of host with the metadata of server. They
+// are placed right below the Host line, indented like the rest of the block.
+func writeAnnotations(host *ssh_config.Host, server domain.Server) {
+	indent := -1
+	nodes := make([]ssh_config.Node, 0, len(host.Nodes))
+	for _, node := range host.Nodes {
+		if _, _, ok := parseAnnotation(node); ok {
+			continue
+		}
+		if kv, ok := node.(*ssh_config.KV); ok && indent < 0 {
+			indent = kv.LeadingSpace
+		}
+		nodes = append(nodes, node)
+	}
+	if indent < 0 {
+		indent = 4
+	}
+
+	lines := make([]string, 0)
+	if len(server.Tags) > 0 {
+		lines = append(lines, "tags="+strings.Join(server.Tags, ","))
+	}
+	if !server.PinnedAt.IsZero() {
+		lines = append(lines, "pinned="+server.PinnedAt.Format(time.RFC3339))
+	}
+	keys := make([]string, 0, len(server.Fields))
+	for key := range server.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("field.%s=%s", key, server.Fields[key]))
+	}
+	if strings.TrimSpace(server.Notes) != "" {
+		for _, line := range strings.Split(strings.TrimRight(server.Notes, "\n"), "\n") {
+			lines = append(lines, "note="+line)
+		}
+	}
+
+	annotations := make([]ssh_config.Node, 0, len(lines))
+	for _, line := range lines {
+		if comment := newComment(indent, " "+annotationPrefix+line); comment != nil {
+			annotations = append(annotations, comment)
+		}
+	}
+	host.Nodes = append(annotations, nodes...)
+}
+
+// hasAnnotations reports whether server carries any metadata that is stored as annotations.
+func hasAnnotations(server domain.Server) bool {
+	return len(server.Tags) > 0 || !server.PinnedAt.IsZero() || server.Notes != "" || len(server.Fields) > 0
+}
+
+// parseAnnotation splits a "# lazyssh:key=value" comment line into its key and value.
+func parseAnnotation(node ssh_config.Node) (string, string, bool) {
+	empty, ok := node.(*ssh_config.Empty)
+	if !ok {
+		return "", "", false
+	}
+	annotation, ok := strings.CutPrefix(strings.TrimLeft(empty.Comment, " \t"), annotationPrefix)
+	if !ok {
+		return "", "", false
+	}
+	key, value, ok := strings.Cut(annotation, "=")
+	return key, value, ok
+}
+
+// newComment builds a comment line indented by indent spaces. The parser keeps the
+// indentation of comment lines unexported, so the line is parsed rather than constructed.
+func newComment(indent int, text string) ssh_config.Node {
+	cfg, err := ssh_config.DecodeBytes([]byte(strings.Repeat(" ", indent) + "#" + text + "\n"))
+	if err != nil || len(cfg.Hosts) == 0 || len(cfg.Hosts[0].Nodes) == 0 {
+		return nil
+	}
+	return cfg.Hosts[0].Nodes[0]
+}
+
+// setPinnedAnnotations pins or unpins servers by rewriting the annotations of their Host blocks,
+// saving each config file once. Metadata of a host that has no annotations yet is taken from
+// the metadata file and moved into the config along with the pin.
+func (r *Repository) setPinnedAnnotations(aliases []string, pinned bool) error {
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	metadata, err := r.metadataManager.loadAll()
+	if err != nil {
+		return fmt.Errorf("load metadata: %w", err)
+	}
+
+	servers := make([]domain.Server, 0, len(aliases))
+	changed := make([]*configFile, 0)
+	for _, alias := range aliases {
+		file, host := r.findConfigFileByAlias(files, alias)
+		if host == nil {
+			return fmt.Errorf("server with alias '%s' not found", alias)
+		}
+		server := domain.Server{Alias: alias}
+		readAnnotations(host, &server)
+		server = r.mergeMetadata([]domain.Server{server}, metadata)[0]
+
+		server.PinnedAt = time.Time{}
+		if pinned {
+			server.PinnedAt = time.Now()
+		}
+		writeAnnotations(host, server)
+		servers = append(servers, server)
+		changed = appendConfigFile(changed, file)
+	}
+
+	for _, file := range changed {
+		if err := r.saveConfig(file); err != nil {
+			r.logger.Warnf("Failed to save config while pinning servers: %v", err)
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+	for _, server := range servers {
+		if err := r.metadataManager.updateServer(server, server.Alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/adapters/data/settings"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func newAnnotatingRepository(t *testing.T, dir string) *Repository {
+	t.Helper()
+	r := newTestRepository(t, dir)
+	r.metadataStore = settings.MetadataStoreConfig
+	r.metadataManager.localOnly = true
+	return r
+}
+
+func TestAnnotationsInConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "config", "Host web\n  # owned by the web team\n  HostName 10.0.0.1\n\nHost db\n  HostName 10.0.0.2\n")
+	writeTestFile(t, dir, "metadata.json", `{"web": {"tags": ["legacy"], "ssh_count": 2}}`)
+	r := newAnnotatingRepository(t, dir)
+
+	// Hosts without annotations still show what the metadata file holds.
+	web := findServer(t, r, "web")
+	if strings.Join(web.Tags, ",") != "legacy" || web.SSHCount != 2 {
+		t.Fatalf("web before annotating = %+v", web)
+	}
+
+	annotated := web
+	annotated.Tags = []string{"prod", "eu"}
+	annotated.Notes = "## Restart\n  indented # not a comment"
+	annotated.Fields = map[string]string{"owner": "alice", "rack": "B12"}
+	if err := r.UpdateServer(web, annotated); err != nil {
+		t.Fatalf("UpdateServer() error = %v", err)
+	}
+	if err := r.SetPinned("web", true); err != nil {
+		t.Fatalf("SetPinned() error = %v", err)
+	}
+	if err := r.RecordSSH("web"); err != nil {
+		t.Fatalf("RecordSSH() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{
+		"Host web\n  # lazyssh:tags=prod,eu\n  # lazyssh:pinned=",
+		"  # lazyssh:field.owner=alice\n  # lazyssh:field.rack=B12\n  # lazyssh:note=## Restart\n  # lazyssh:note=  indented # not a comment\n  # owned by the web team\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("config does not contain %q:\n%s", want, data)
+		}
+	}
+
+	web = findServer(t, r, "web")
+	if strings.Join(web.Tags, ",") != "prod,eu" || web.Notes != annotated.Notes || web.Fields["rack"] != "B12" || web.PinnedAt.IsZero() || web.SSHCount != 3 {
+		t.Errorf("web read back = %+v", web)
+	}
+	metadata, _ := r.metadataManager.loadAll()
+	if meta := metadata["web"]; meta.Tags != nil || meta.PinnedAt != "" || meta.SSHCount != 3 {
+		t.Errorf("metadata file still holds annotations: %+v", meta)
+	}
+
+	if err := r.SetPinned("web", false); err != nil {
+		t.Fatalf("SetPinned(false) error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "lazyssh:pinned") {
+		t.Errorf("unpinning left the pin in the config:\n%s", data)
+	}
+	if db := findServer(t, r, "db"); hasAnnotations(db) {
+		t.Errorf("db picked up annotations: %+v", db)
+	}
+}
+
+func TestAnnotationsIgnoredInFileMode(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "config", "Host web\n    # lazyssh:tags=prod\n    HostName 10.0.0.1\n")
+	r := newTestRepository(t, dir)
+
+	if web := findServer(t, r, "web"); len(web.Tags) != 0 {
+		t.Errorf("tags = %v, want annotations ignored when metadata is kept in the file", web.Tags)
+	}
+}
+
+func findServer(t *testing.T, r *Repository, alias string) domain.Server {
+	t.Helper()
+	servers, err := r.ListServers()
+	if err != nil {
+		t.Fatalf("ListServers() error = %v", err)
+	}
+	for _, server := range servers {
+		if server.Alias == alias {
+			return server
+		}
+	}
+	t.Fatalf("server %s not found", alias)
+	return domain.Server{}
+}
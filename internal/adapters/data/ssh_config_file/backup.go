@@ -15,6 +15,7 @@
 package ssh_config_file
 
 import (
+	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
@@ -24,40 +25,68 @@ import (
 	"time"
 )
 
-// createBackup creates a timestamped backup of the current config file
-func (r *Repository) createBackup() error {
-	if _, err := r.fileSystem.Stat(r.configPath); os.IsNotExist(err) {
+// includeBackupDir, beside the metadata file, holds the backups of included config files.
+const includeBackupDir = "backups"
+
+// createBackup creates a timestamped backup of the given config file and rotates
+// older backups of that same file according to the backup settings.
+func (r *Repository) createBackup(configPath string) error {
+	if _, err := r.fileSystem.Stat(configPath); os.IsNotExist(err) {
 		return nil
 	} else if err != nil {
 		return fmt.Errorf("failed to check if config file exists: %w", err)
 	}
 
+	dir, baseName := r.backupLocation(configPath)
+	if err := r.ensureBackupDir(dir); err != nil {
+		return err
+	}
 	timestamp := time.Now().UnixMilli()
-	backupPath := fmt.Sprintf("%s-%d-%s", r.configPath, timestamp, BackupSuffix)
+	backupPath := filepath.Join(dir, fmt.Sprintf("%s-%d-%s", baseName, timestamp, BackupSuffix))
 
-	if err := r.copyFile(r.configPath, backupPath); err != nil {
+	copyBackup := r.copyFile
+	if r.backups.Compress {
+		backupPath += CompressedSuffix
+		copyBackup = r.compressFile
+	}
+	if err := copyBackup(configPath, backupPath); err != nil {
 		return fmt.Errorf("failed to copy config to backup: %w", err)
 	}
 
 	r.logger.Infof("Created backup: %s", backupPath)
 
-	configDir := filepath.Dir(r.configPath)
-
-	backupFiles, err := r.findBackupFiles(configDir)
-	if err != nil {
+	if err := r.pruneBackups(configPath); err != nil {
 		return err
 	}
+	r.recordBackup(backupPath)
+	return nil
+}
 
-	if len(backupFiles) <= MaxBackups {
-		return nil
+// pruneBackups removes the rolling backups of configPath beyond the configured count or age.
+// The newest backup is always kept.
+func (r *Repository) pruneBackups(configPath string) error {
+	dir, baseName := r.backupLocation(configPath)
+
+	backupFiles, err := r.findBackupFiles(dir, baseName)
+	if err != nil {
+		return err
 	}
 
 	sort.Slice(backupFiles, func(i, j int) bool {
-		return backupFiles[i].ModTime().After(backupFiles[j].ModTime())
+		return backupTime(backupFiles[i], baseName).After(backupTime(backupFiles[j], baseName))
 	})
 
-	for i := MaxBackups; i < len(backupFiles); i++ {
-		backupPath := filepath.Join(configDir, backupFiles[i].Name())
+	var cutoff time.Time
+	if r.backups.MaxAge > 0 {
+		cutoff = time.Now().Add(-time.Duration(r.backups.MaxAge))
+	}
+	for i := 1; i < len(backupFiles); i++ {
+		tooMany := r.backups.MaxCount > 0 && i >= r.backups.MaxCount
+		tooOld := !cutoff.IsZero() && backupTime(backupFiles[i], baseName).Before(cutoff)
+		if !tooMany && !tooOld {
+			continue
+		}
+		backupPath := filepath.Join(dir, backupFiles[i].Name())
 		if err := r.fileSystem.Remove(backupPath); err != nil {
 			r.logger.Warnf("failed to remove old backup %s: %v", backupPath, err)
 			continue
@@ -102,8 +131,85 @@ func (r *Repository) copyFile(src, dst string) error {
 	return destFile.Sync()
 }
 
-// findBackupFiles finds all backup files for the given config file
-func (r *Repository) findBackupFiles(dir string) ([]os.FileInfo, error) {
+// compressFile writes a gzip-compressed copy of src to dst.
+func (r *Repository) compressFile(src, dst string) error {
+	srcFile, err := r.fileSystem.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := srcFile.Close(); cerr != nil {
+			r.logger.Warnf("failed to close source file %s: %v", src, cerr)
+		}
+	}()
+
+	srcInfo, err := r.fileSystem.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	destFile, err := r.fileSystem.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, srcInfo.Mode())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := destFile.Close(); cerr != nil {
+			r.logger.Warnf("failed to close destination file %s: %v", dst, cerr)
+		}
+	}()
+
+	gz := gzip.NewWriter(destFile)
+	if _, err := io.Copy(gz, srcFile); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	return destFile.Sync()
+}
+
+// backupLocation returns the directory the backups of configPath are written to and the name
+// they are based on. By default backups of the main config sit beside it and share its name.
+// Backups of included files never do: an Include glob such as config.d/* would pick them up,
+// and both lazyssh and ssh would read the old hosts again. They go to includeBackupDir beside
+// the metadata file instead. Outside the config's own directory the name is the file's path
+// relative to the main config's directory, with separators replaced, so included files with
+// the same name keep separate backups.
+func (r *Repository) backupLocation(configPath string) (dir, baseName string) {
+	dir = r.backups.Dir
+	if dir == "" {
+		if filepath.Clean(configPath) == filepath.Clean(r.configPath) {
+			return filepath.Dir(configPath), filepath.Base(configPath)
+		}
+		dir = filepath.Join(filepath.Dir(r.metadataManager.filePath), includeBackupDir)
+	}
+	name := filepath.Clean(configPath)
+	if rel, err := filepath.Rel(filepath.Dir(r.configPath), name); err == nil && !strings.HasPrefix(rel, "..") {
+		name = rel
+	}
+	return dir, strings.Trim(strings.ReplaceAll(name, string(filepath.Separator), "_"), "_")
+}
+
+// originalBackupPath returns the path of the one-time original backup of configPath.
+func (r *Repository) originalBackupPath(configPath string) string {
+	dir, baseName := r.backupLocation(configPath)
+	return filepath.Join(dir, baseName+OriginalBackupSuffix)
+}
+
+// ensureBackupDir creates a backup directory other than the main config's, which already exists.
+func (r *Repository) ensureBackupDir(dir string) error {
+	if filepath.Clean(dir) == filepath.Dir(filepath.Clean(r.configPath)) {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	return nil
+}
+
+// findBackupFiles finds all rolling backups of the config file named baseName in dir.
+func (r *Repository) findBackupFiles(dir, baseName string) ([]os.FileInfo, error) {
 	entries, err := r.fileSystem.ReadDir(dir)
 	if err != nil {
 		return nil, err
@@ -113,7 +219,7 @@ func (r *Repository) findBackupFiles(dir string) ([]os.FileInfo, error) {
 
 	for _, entry := range entries {
 		name := entry.Name()
-		if strings.HasSuffix(name, BackupSuffix) {
+		if isBackupOf(name, baseName) {
 			info, err := entry.Info()
 			if err != nil {
 				r.logger.Warnf("failed to get info for backup file %s: %v", name, err)
@@ -126,17 +232,45 @@ func (r *Repository) findBackupFiles(dir string) ([]os.FileInfo, error) {
 	return backupFiles, nil
 }
 
-// createOriginalBackupIfNeeded creates a one-time original backup of the current SSH config.
-func (r *Repository) createOriginalBackupIfNeeded() error {
+// isLazysshFile reports whether name is a backup or temporary file lazyssh writes, which
+// must not be read as a config file even if an Include pattern matches it.
+func isLazysshFile(name string) bool {
+	name = strings.TrimSuffix(name, CompressedSuffix)
+	return strings.HasSuffix(name, "-"+BackupSuffix) || strings.HasSuffix(name, OriginalBackupSuffix) ||
+		strings.HasSuffix(name, TempSuffix)
+}
+
+// isBackupOf reports whether name is a rolling backup (<base>-<timestamp>-lazyssh.backup,
+// optionally gzipped) of baseName.
+func isBackupOf(name, baseName string) bool {
+	name = strings.TrimSuffix(name, CompressedSuffix)
+	prefix := baseName + "-"
+	suffix := "-" + BackupSuffix
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+		return false
+	}
+	timestamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), suffix)
+	if timestamp == "" {
+		return false
+	}
+	for _, c := range timestamp {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// createOriginalBackupIfNeeded creates a one-time original backup of the given SSH config file.
+func (r *Repository) createOriginalBackupIfNeeded(configPath string) error {
 	// If no SSH config file, nothing to do.
-	if _, err := r.fileSystem.Stat(r.configPath); os.IsNotExist(err) {
+	if _, err := r.fileSystem.Stat(configPath); os.IsNotExist(err) {
 		return nil
 	} else if err != nil {
 		return fmt.Errorf("failed to check if config file exists: %w", err)
 	}
 
-	configDir := filepath.Dir(r.configPath)
-	originalBackupPath := filepath.Join(configDir, OriginalBackupName)
+	originalBackupPath := r.originalBackupPath(configPath)
 
 	if _, err := r.fileSystem.Stat(originalBackupPath); err == nil {
 		return nil
@@ -144,7 +278,10 @@ func (r *Repository) createOriginalBackupIfNeeded() error {
 		return fmt.Errorf("failed to check if original backup exists: %w", err)
 	}
 
-	if err := r.copyFile(r.configPath, originalBackupPath); err != nil {
+	if err := r.ensureBackupDir(filepath.Dir(originalBackupPath)); err != nil {
+		return err
+	}
+	if err := r.copyFile(configPath, originalBackupPath); err != nil {
 		return fmt.Errorf("failed to create original backup: %w", err)
 	}
 
@@ -0,0 +1,234 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// backupLogName is the file, beside the metadata file, that records which change produced each backup.
+const backupLogName = "backups.json"
+
+// ListBackups returns the backups of the main config and its includes, newest first.
+// The one-time original backup of each file is listed after its rolling backups.
+func (r *Repository) ListBackups() ([]domain.Backup, error) {
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	operations := r.loadBackupLog()
+	backups := make([]domain.Backup, 0)
+	for _, file := range files {
+		dir, baseName := r.backupLocation(file.path)
+		infos, err := r.findBackupFiles(dir, baseName)
+		if err != nil && !r.fileSystem.IsNotExist(err) {
+			r.logger.Warnf("failed to list backups of %s: %v", file.path, err)
+		}
+		for _, info := range infos {
+			path := filepath.Join(dir, info.Name())
+			backups = append(backups, domain.Backup{
+				Path:       path,
+				ConfigFile: file.path,
+				CreatedAt:  backupTime(info, baseName),
+				Size:       info.Size(),
+				Operation:  operations[path],
+			})
+		}
+
+		original := r.originalBackupPath(file.path)
+		if info, err := r.fileSystem.Stat(original); err == nil {
+			backups = append(backups, domain.Backup{
+				Path:       original,
+				ConfigFile: file.path,
+				CreatedAt:  info.ModTime(),
+				Size:       info.Size(),
+				Original:   true,
+			})
+		}
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		if backups[i].Original != backups[j].Original {
+			return !backups[i].Original
+		}
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// BackupDiff returns a unified diff from the current config file to the backup,
+// i.e. the changes restoring the backup would make.
+func (r *Repository) BackupDiff(backup domain.Backup) (string, error) {
+	if err := r.checkBackup(backup); err != nil {
+		return "", err
+	}
+	content, err := r.readBackup(backup.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read backup: %w", err)
+	}
+	current, err := r.readFileContent(backup.ConfigFile)
+	if err != nil && !r.fileSystem.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read config: %w", err)
+	}
+	return unifiedDiff(backup.ConfigFile, backup.Path, current, content), nil
+}
+
+// RestoreBackup replaces a config file with one of its backups. The restore is an ordinary
+// atomic save: the current content is backed up first and the change can be undone.
+func (r *Repository) RestoreBackup(backup domain.Backup) error {
+	if err := r.checkBackup(backup); err != nil {
+		return err
+	}
+	if !r.isWritable(backup.ConfigFile) {
+		return fmt.Errorf("config file '%s' is not writable", backup.ConfigFile)
+	}
+
+	description := fmt.Sprintf("restore %s from %s", filepath.Base(backup.ConfigFile), filepath.Base(backup.Path))
+	return r.tracked(description, func() error {
+		content, err := r.readBackup(backup.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read backup: %w", err)
+		}
+		if err := r.writeConfig(backup.ConfigFile, content); err != nil {
+			r.logger.Warnf("Failed to save config while restoring backup: %v", err)
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		return nil
+	})
+}
+
+// checkBackup makes sure backup is a lazyssh backup of one of the loaded config files,
+// so that neither an arbitrary file is read nor an arbitrary file overwritten.
+func (r *Repository) checkBackup(backup domain.Backup) error {
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if _, err := findConfigFile(files, backup.ConfigFile); err != nil {
+		return err
+	}
+
+	dir, baseName := r.backupLocation(backup.ConfigFile)
+	name := filepath.Base(backup.Path)
+	if filepath.Dir(filepath.Clean(backup.Path)) != filepath.Clean(dir) ||
+		(!isBackupOf(name, baseName) && name != baseName+OriginalBackupSuffix) {
+		return fmt.Errorf("'%s' is not a lazyssh backup of '%s'", backup.Path, backup.ConfigFile)
+	}
+	return nil
+}
+
+// backupTime returns when a rolling backup was taken, from the timestamp in its name
+// or, failing that, its modification time.
+func backupTime(info os.FileInfo, baseName string) time.Time {
+	if !isBackupOf(info.Name(), baseName) {
+		return info.ModTime()
+	}
+	name := strings.TrimSuffix(info.Name(), CompressedSuffix)
+	timestamp := strings.TrimSuffix(strings.TrimPrefix(name, baseName+"-"), "-"+BackupSuffix)
+	millis, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return info.ModTime()
+	}
+	return time.UnixMilli(millis)
+}
+
+// readBackup returns the content of a backup, decompressing gzipped ones.
+func (r *Repository) readBackup(path string) ([]byte, error) {
+	content, err := r.readFileContent(path)
+	if err != nil || !strings.HasSuffix(path, CompressedSuffix) {
+		return content, err
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(gz)
+}
+
+func (r *Repository) readFileContent(path string) ([]byte, error) {
+	if content, ok := r.preview[path]; ok {
+		return content, nil
+	}
+	file, err := r.fileSystem.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			r.logger.Warnf("failed to close %s: %v", path, cerr)
+		}
+	}()
+	return io.ReadAll(file)
+}
+
+func (r *Repository) backupLogPath() string {
+	return filepath.Join(filepath.Dir(r.metadataManager.filePath), backupLogName)
+}
+
+// loadBackupLog reads the backup log, a map from backup path to the change that produced it.
+// A missing or unreadable log only means operations are shown as unknown.
+func (r *Repository) loadBackupLog() map[string]string {
+	operations := make(map[string]string)
+	data, err := os.ReadFile(r.backupLogPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			r.logger.Warnf("failed to read backup log: %v", err)
+		}
+		return operations
+	}
+	if err := json.Unmarshal(data, &operations); err != nil {
+		r.logger.Warnf("failed to parse backup log: %v", err)
+		return make(map[string]string)
+	}
+	return operations
+}
+
+// recordBackup adds backupPath to the backup log with the change currently being made,
+// dropping entries whose backups have since been rotated away.
+func (r *Repository) recordBackup(backupPath string) {
+	operations := r.loadBackupLog()
+	for path := range operations {
+		if _, err := r.fileSystem.Stat(path); err != nil {
+			delete(operations, path)
+		}
+	}
+	operations[backupPath] = r.operation
+
+	data, err := json.MarshalIndent(operations, "", "  ")
+	if err != nil {
+		r.logger.Warnf("failed to marshal backup log: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(r.backupLogPath()), 0o750); err != nil {
+		r.logger.Warnf("failed to create backup log directory: %v", err)
+		return
+	}
+	if err := os.WriteFile(r.backupLogPath(), data, 0o600); err != nil {
+		r.logger.Warnf("failed to write backup log: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/adapters/data/settings"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestBackupsListDiffRestore(t *testing.T) {
+	dir := t.TempDir()
+	content := "Host web\n    HostName 10.0.0.1\n"
+	path := writeTestFile(t, dir, "config", content)
+	r := newTestRepository(t, dir)
+
+	if err := r.AddServer(domain.Server{Alias: "db", Host: "10.0.0.2"}); err != nil {
+		t.Fatalf("AddServer() error = %v", err)
+	}
+
+	backups, err := r.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups() error = %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("ListBackups() returned %d backups, want a rolling and an original one", len(backups))
+	}
+	latest, original := backups[0], backups[1]
+	if latest.Original || latest.Operation != "add server db" || latest.ConfigFile != path || latest.Size != int64(len(content)) {
+		t.Errorf("latest backup = %+v, want a backup of %s taken before adding db", latest, path)
+	}
+	if !original.Original {
+		t.Errorf("last backup = %+v, want the original backup", original)
+	}
+
+	diff, err := r.BackupDiff(latest)
+	if err != nil {
+		t.Fatalf("BackupDiff() error = %v", err)
+	}
+	if !strings.Contains(diff, "-    HostName 10.0.0.2\n") || strings.Contains(diff, "-Host web") {
+		t.Errorf("BackupDiff() should only remove the added host:\n%s", diff)
+	}
+
+	if err := r.RestoreBackup(latest); err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("config after restore = %q, want %q", data, content)
+	}
+	backups, _ = r.ListBackups()
+	if len(backups) != 3 || !strings.HasPrefix(backups[0].Operation, "restore config from ") {
+		t.Errorf("restore should be backed up first, backups = %+v", backups)
+	}
+	if desc, err := r.Undo(); err != nil || !strings.HasPrefix(desc, "restore config") {
+		t.Errorf("Undo() = %q, %v, want the restore undone", desc, err)
+	}
+
+	outside := writeTestFile(t, dir, "notes.txt", "secret")
+	if err := r.RestoreBackup(domain.Backup{Path: outside, ConfigFile: path}); err == nil {
+		t.Errorf("RestoreBackup() accepted a file that is not a backup")
+	}
+}
+
+func TestBackupRetention(t *testing.T) {
+	day := settings.Duration(24 * time.Hour)
+	tests := []struct {
+		name      string
+		backups   settings.Backup
+		wantCount int
+	}{
+		{"count limit", settings.Backup{MaxCount: 3}, 3},
+		{"age limit", settings.Backup{MaxAge: day}, 2},
+		{"count and age", settings.Backup{MaxCount: 1, MaxAge: day}, 1},
+		{"no limits", settings.Backup{}, 6},
+		{"shared directory, compressed", settings.Backup{MaxCount: 3, Compress: true}, 3},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		content := "Host web\n    HostName 10.0.0.1\n"
+		path := writeTestFile(t, dir, "config", content)
+		r := newTestRepository(t, dir)
+		r.backups = tt.backups
+		if tt.backups.Compress {
+			r.backups.Dir = filepath.Join(dir, "backups")
+		}
+
+		// Five backups from earlier days; the save below adds a sixth.
+		backupDir, baseName := r.backupLocation(path)
+		if err := os.MkdirAll(backupDir, 0o700); err != nil {
+			t.Fatal(err)
+		}
+		for i := 1; i <= 5; i++ {
+			taken := time.Now().Add(-time.Duration(i) * 12 * time.Hour).UnixMilli()
+			writeTestFile(t, backupDir, fmt.Sprintf("%s-%d-%s", baseName, taken, BackupSuffix), content)
+		}
+
+		if err := r.AddServer(domain.Server{Alias: "db", Host: "10.0.0.2"}); err != nil {
+			t.Fatalf("%s: AddServer() error = %v", tt.name, err)
+		}
+		backups, err := r.ListBackups()
+		if err != nil {
+			t.Fatalf("%s: ListBackups() error = %v", tt.name, err)
+		}
+		rolling := backups[:len(backups)-1]
+		if len(rolling) != tt.wantCount {
+			t.Errorf("%s: kept %d rolling backups, want %d", tt.name, len(rolling), tt.wantCount)
+		}
+		if newest := rolling[0]; newest.Operation != "add server db" {
+			t.Errorf("%s: newest backup = %+v, want the one taken before adding db", tt.name, newest)
+		}
+		if !backups[len(backups)-1].Original || filepath.Dir(backups[len(backups)-1].Path) != backupDir {
+			t.Errorf("%s: original backup = %+v, want it in %s", tt.name, backups[len(backups)-1], backupDir)
+		}
+
+		diff, err := r.BackupDiff(rolling[0])
+		if err != nil || !strings.Contains(diff, "-    HostName 10.0.0.2\n") {
+			t.Errorf("%s: BackupDiff() = %q, %v", tt.name, diff, err)
+		}
+	}
+}
+
+func TestBackupLocation(t *testing.T) {
+	r := &Repository{
+		configPath:      "/home/u/.ssh/config",
+		metadataManager: newMetadataManager("/home/u/.lazyssh/metadata.json", zap.NewNop().Sugar()),
+	}
+	if dir, name := r.backupLocation("/home/u/.ssh/config"); dir != "/home/u/.ssh" || name != "config" {
+		t.Errorf("backupLocation() of the main config = %s, %s, want beside it", dir, name)
+	}
+	if dir, name := r.backupLocation("/home/u/.ssh/config.d/work"); dir != "/home/u/.lazyssh/backups" || name != "config.d_work" {
+		t.Errorf("backupLocation() of an included file = %s, %s, want /home/u/.lazyssh/backups, config.d_work", dir, name)
+	}
+
+	r.backups.Dir = "/backups"
+	tests := map[string]string{
+		"/home/u/.ssh/config":        "config",
+		"/home/u/.ssh/config.d/work": "config.d_work",
+		"/etc/ssh/ssh_config.d/work": "etc_ssh_ssh_config.d_work",
+	}
+	for path, want := range tests {
+		if dir, name := r.backupLocation(path); dir != "/backups" || name != want {
+			t.Errorf("backupLocation(%s) = %s, %s, want /backups, %s", path, dir, name, want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// UpdateServers updates several servers at once, saving each config file they live in
+// once, so the whole change takes one backup per file and one step to undo. Servers
+// cannot be renamed this way.
+func (r *Repository) UpdateServers(servers []domain.Server, newServers []domain.Server) error {
+	if len(servers) != len(newServers) {
+		return fmt.Errorf("got %d servers to update but %d new versions", len(servers), len(newServers))
+	}
+	return r.tracked(describeBulkUpdate(servers, newServers), func() error {
+		return r.updateServers(servers, newServers)
+	})
+}
+
+func (r *Repository) updateServers(servers []domain.Server, newServers []domain.Server) error {
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	changed := make([]*configFile, 0)
+	for i, server := range servers {
+		newServer := newServers[i]
+		if newServer.Alias != server.Alias {
+			return fmt.Errorf("server '%s' cannot be renamed in a bulk update", server.Alias)
+		}
+		file, host := r.findConfigFileByAlias(files, server.Alias)
+		if host == nil {
+			return fmt.Errorf("server with alias '%s' not found", server.Alias)
+		}
+		r.updateHostNodes(host, newServer)
+		if r.annotationsInConfig() {
+			writeAnnotations(host, newServer)
+		}
+		changed = appendConfigFile(changed, file)
+	}
+
+	for _, file := range changed {
+		if err := r.saveConfig(file); err != nil {
+			r.logger.Warnf("Failed to save config while updating servers: %v", err)
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+	for _, newServer := range newServers {
+		if err := r.metadataManager.updateServer(newServer, newServer.Alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteServers removes several servers at once, saving each config file they live in once.
+// The change can be undone in one step.
+func (r *Repository) DeleteServers(servers []domain.Server) error {
+	return r.tracked(fmt.Sprintf("delete %d servers", len(servers)), func() error {
+		return r.deleteServers(servers)
+	})
+}
+
+func (r *Repository) deleteServers(servers []domain.Server) error {
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	changed := make([]*configFile, 0)
+	for _, server := range servers {
+		file, _ := r.findConfigFileByAlias(files, server.Alias)
+		if file == nil {
+			return fmt.Errorf("server with alias '%s' not found", server.Alias)
+		}
+		file.cfg.Hosts = r.removeHostByAlias(file.cfg.Hosts, server.Alias)
+		changed = appendConfigFile(changed, file)
+	}
+
+	for _, file := range changed {
+		if err := r.saveConfig(file); err != nil {
+			r.logger.Warnf("Failed to save config while deleting servers: %v", err)
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+	for _, server := range servers {
+		if err := r.metadataManager.deleteServer(server.Alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetPinnedServers pins or unpins several servers at once. The change can be undone in one step.
+func (r *Repository) SetPinnedServers(aliases []string, pinned bool) error {
+	description := fmt.Sprintf("unpin %d servers", len(aliases))
+	if pinned {
+		description = fmt.Sprintf("pin %d servers", len(aliases))
+	}
+	return r.tracked(description, func() error {
+		if r.annotationsInConfig() {
+			return r.setPinnedAnnotations(aliases, pinned)
+		}
+		for _, alias := range aliases {
+			if err := r.metadataManager.setPinned(alias, pinned); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// describeBulkUpdate names a bulk update for the undo history, calling out tag-only edits.
+func describeBulkUpdate(servers, newServers []domain.Server) string {
+	for i := range servers {
+		if !strings.HasPrefix(describeUpdate(servers[i], newServers[i]), "edit tags of ") {
+			return fmt.Sprintf("edit %d servers", len(servers))
+		}
+	}
+	return fmt.Sprintf("edit tags of %d servers", len(servers))
+}
+
+// appendConfigFile adds file to files unless it is already listed.
+func appendConfigFile(files []*configFile, file *configFile) []*configFile {
+	for _, f := range files {
+		if f == file {
+			return files
+		}
+	}
+	return append(files, file)
+}
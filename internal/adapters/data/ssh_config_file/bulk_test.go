@@ -0,0 +1,80 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestBulkOperations(t *testing.T) {
+	dir := t.TempDir()
+	content := "Host web1\n    HostName 10.0.0.1\n\nHost web2\n    HostName 10.0.0.2\n\nHost db\n    HostName 10.0.0.3\n"
+	path := writeTestFile(t, dir, "config", content)
+	r := newTestRepository(t, dir)
+
+	servers, err := r.ListServers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	webs := servers[:2]
+	updated := make([]domain.Server, len(webs))
+	for i, server := range webs {
+		updated[i] = server
+		updated[i].User = "deploy"
+		updated[i].Tags = []string{"web"}
+	}
+	if err := r.UpdateServers(webs, updated); err != nil {
+		t.Fatalf("UpdateServers() error = %v", err)
+	}
+	if err := r.SetPinnedServers([]string{"web1", "web2"}, true); err != nil {
+		t.Fatalf("SetPinnedServers() error = %v", err)
+	}
+	servers, _ = r.ListServers()
+	for _, server := range servers[:2] {
+		if server.User != "deploy" || len(server.Tags) != 1 || server.PinnedAt.IsZero() {
+			t.Errorf("server after bulk update = %+v, want user deploy, tagged web and pinned", server)
+		}
+	}
+
+	before, _ := r.ListBackups()
+	if err := r.DeleteServers(servers); err != nil {
+		t.Fatalf("DeleteServers() error = %v", err)
+	}
+	after, _ := r.ListBackups()
+	if len(after) != len(before)+1 || after[0].Operation != "delete 3 servers" {
+		t.Errorf("DeleteServers() took %d backups (newest %+v), want one", len(after)-len(before), after[0])
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "Host ") {
+		t.Errorf("config after DeleteServers() still has hosts:\n%s", data)
+	}
+
+	for _, want := range []string{"delete 3 servers", "pin 2 servers", "edit 2 servers"} {
+		if desc, err := r.Undo(); err != nil || desc != want {
+			t.Errorf("Undo() = %q, %v, want %q", desc, err, want)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("config after undoing the bulk changes = %q, want %q", data, content)
+	}
+
+	renamed := []domain.Server{{Alias: "www"}}
+	if err := r.UpdateServers(servers[:1], renamed); err == nil {
+		t.Errorf("UpdateServers() renamed a server, want error")
+	}
+}
@@ -15,43 +15,238 @@
 package ssh_config_file
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/Adembc/lazyssh/internal/fsutil"
 	"github.com/kevinburke/ssh_config"
 )
 
-// loadConfig reads and parses the SSH config file.
-// If the file does not exist, it returns an empty config without error to support first-run behavior.
-func (r *Repository) loadConfig() (*ssh_config.Config, error) {
-	file, err := r.fileSystem.Open(r.configPath)
+// ErrConfigChanged is returned when a config file changed on disk after lazyssh read it.
+var ErrConfigChanged = errors.New("config changed on disk")
+
+// configFile is a parsed SSH config file together with the path it was read from.
+type configFile struct {
+	path string
+	cfg  *ssh_config.Config
+	hash string // content hash when read, see contentHash
+}
+
+// loadConfigFile reads and parses a single SSH config file.
+// A missing file yields an empty config so that callers can treat it as having no hosts;
+// this supports first-run behavior when even the main config does not exist yet.
+func (r *Repository) loadConfigFile(path string) (*configFile, error) {
+	content, err := r.readFileContent(path)
 	if err != nil {
 		if r.fileSystem.IsNotExist(err) {
-			return &ssh_config.Config{Hosts: []*ssh_config.Host{}}, nil
+			return &configFile{path: path, cfg: &ssh_config.Config{Hosts: []*ssh_config.Host{}}}, nil
 		}
 		return nil, fmt.Errorf("failed to open config file: %w", err)
 	}
-	defer func() {
-		if cerr := file.Close(); cerr != nil {
-			r.logger.Warnf("failed to close config file: %v", cerr)
-		}
-	}()
 
-	cfg, err := ssh_config.Decode(file)
+	cfg, err := ssh_config.DecodeBytes(content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode config: %w", err)
 	}
+	restoreNegatedPatterns(cfg)
+	splitMatchBlocks(cfg)
+
+	return &configFile{path: path, cfg: cfg, hash: contentHash(content)}, nil
+}
+
+// restoreNegatedPatterns puts back the leading "!" the parser strips from the text of
+// negated Host patterns, so they are displayed and written back unchanged. Matching is
+// unaffected since the parser keeps the negation separately.
+func restoreNegatedPatterns(cfg *ssh_config.Config) {
+	for _, host := range cfg.Hosts {
+		for _, pattern := range host.Patterns {
+			if strings.HasPrefix(pattern.Str, "!") {
+				continue
+			}
+			// A pattern always matches its own text, so a single-pattern Host that
+			// rejects it must be negated.
+			probe := &ssh_config.Host{Patterns: []*ssh_config.Pattern{pattern}}
+			if !probe.Matches(pattern.Str) {
+				pattern.Str = "!" + pattern.Str
+			}
+		}
+	}
+}
+
+// loadConfigFiles loads the main SSH config and, recursively, every file pulled in
+// through Include directives. The main config is always first; included files follow
+// in the order their Include directives appear. Files already visited are skipped so
+// that Include cycles cannot recurse forever.
+func (r *Repository) loadConfigFiles() ([]*configFile, error) {
+	root, err := r.loadConfigFile(r.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []*configFile{root}
+	visited := map[string]bool{filepath.Clean(r.configPath): true}
+	r.collectIncludedFiles(root, visited, &files)
+	return files, nil
+}
+
+// collectIncludedFiles appends the files included by parent to files, depth-first.
+func (r *Repository) collectIncludedFiles(parent *configFile, visited map[string]bool, files *[]*configFile) {
+	for _, host := range parent.cfg.Hosts {
+		for _, node := range host.Nodes {
+			inc, ok := node.(*ssh_config.Include)
+			if !ok {
+				continue
+			}
+			for _, path := range r.resolveInclude(includePatterns(inc)) {
+				if visited[path] {
+					r.logger.Warnf("skipping already included config file %s (included from %s)", path, parent.path)
+					continue
+				}
+				visited[path] = true
+
+				child, err := r.loadConfigFile(path)
+				if err != nil {
+					r.logger.Warnf("failed to load included config file %s: %v", path, err)
+					continue
+				}
+				*files = append(*files, child)
+				r.collectIncludedFiles(child, visited, files)
+			}
+		}
+	}
+}
+
+// resolveInclude expands the file patterns of an Include directive into concrete file paths.
+// Relative patterns are resolved against the directory of the main config (~/.ssh), as OpenSSH does
+// for user configuration files. Backups and temporary files of lazyssh are skipped.
+func (r *Repository) resolveInclude(patterns []string) []string {
+	baseDir := filepath.Dir(r.configPath)
+	paths := make([]string, 0)
+
+	for _, pattern := range patterns {
+		pattern = fsutil.ExpandHome(pattern)
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(baseDir, pattern)
+		}
+
+		matches, err := r.fileSystem.Glob(pattern)
+		if err != nil {
+			r.logger.Warnf("invalid Include pattern %q: %v", pattern, err)
+			continue
+		}
+		sort.Strings(matches)
+
+		for _, match := range matches {
+			if isLazysshFile(filepath.Base(match)) {
+				continue
+			}
+			info, err := r.fileSystem.Stat(match)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			paths = append(paths, filepath.Clean(match))
+		}
+	}
 
-	return cfg, nil
+	return paths
 }
 
-// saveConfig writes the SSH config back to the file with atomic operations and backup management.
-func (r *Repository) saveConfig(cfg *ssh_config.Config) error {
-	configDir := filepath.Dir(r.configPath)
+// includePatterns extracts the file patterns from an Include directive.
+// The parser keeps them private, so they are recovered from the directive's textual form.
+func includePatterns(inc *ssh_config.Include) []string {
+	line := inc.String()
+	if idx := strings.Index(line, "#"); idx >= 0 {
+		line = line[:idx]
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) > 0 && strings.EqualFold(fields[0], "include") {
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && fields[0] == "=" {
+		fields = fields[1:]
+	}
+	return fields
+}
+
+// findConfigFileByAlias returns the first loaded config file that defines a Host with the given alias.
+func (r *Repository) findConfigFileByAlias(files []*configFile, alias string) (*configFile, *ssh_config.Host) {
+	for _, file := range files {
+		if host := r.findHostByAlias(file.cfg, alias); host != nil {
+			return file, host
+		}
+	}
+	return nil, nil
+}
+
+// targetConfigFile picks the loaded config file new hosts are appended to.
+// An empty path selects the main config; any other path must be one of the loaded files.
+func (r *Repository) targetConfigFile(files []*configFile, path string) (*configFile, error) {
+	if path == "" {
+		return files[0], nil
+	}
+	file, err := findConfigFile(files, path)
+	if err != nil {
+		return nil, err
+	}
+	if !r.isWritable(file.path) {
+		return nil, fmt.Errorf("config file '%s' is not writable", path)
+	}
+	return file, nil
+}
+
+// findConfigFile returns the loaded config file at path.
+func findConfigFile(files []*configFile, path string) (*configFile, error) {
+	for _, file := range files {
+		if filepath.Clean(file.path) == filepath.Clean(path) {
+			return file, nil
+		}
+	}
+	return nil, fmt.Errorf("config file '%s' is not part of the SSH config", path)
+}
+
+// isWritable reports whether lazyssh may write the given config file.
+// Files that do not exist yet (e.g. a missing main config) are writable; system-wide configs are not.
+func (r *Repository) isWritable(path string) bool {
+	if strings.HasPrefix(filepath.Clean(path), SystemConfigDir) {
+		return false
+	}
+	info, err := r.fileSystem.Stat(path)
+	if err != nil {
+		return r.fileSystem.IsNotExist(err)
+	}
+	return info.Mode().Perm()&0o200 != 0
+}
+
+// saveConfig writes a loaded SSH config file back to disk with atomic operations and backup management.
+// If the file changed on disk since it was loaded, the write is rejected with ErrConfigChanged
+// rather than silently discarding the other change.
+func (r *Repository) saveConfig(file *configFile) error {
+	current, err := r.readFileContent(file.path)
+	if err != nil && !r.fileSystem.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if contentHash(current) != file.hash {
+		return fmt.Errorf("%w: %s", ErrConfigChanged, file.path)
+	}
+	return r.writeConfig(file.path, []byte(file.cfg.String()))
+}
+
+// writeConfig atomically replaces an SSH config file with content, creating backups first.
+func (r *Repository) writeConfig(path string, content []byte) error {
+	if r.preview != nil {
+		r.preview[path] = content
+		return nil
+	}
+
+	configDir := filepath.Dir(path)
 
-	tempFile, err := r.createTempFile(configDir)
+	tempFile, err := r.createTempFile(configDir, filepath.Base(path))
 	if err != nil {
 		return fmt.Errorf("failed to create temporary file: %w", err)
 	}
@@ -62,29 +257,29 @@ func (r *Repository) saveConfig(cfg *ssh_config.Config) error {
 		}
 	}()
 
-	if err := r.writeConfigToFile(tempFile, cfg); err != nil {
+	if err := r.writeConfigToFile(tempFile, content); err != nil {
 		return fmt.Errorf("failed to write config to temporary file: %w", err)
 	}
 
 	// Ensure a one-time original backup exists before any modifications managed by lazyssh.
-	if err := r.createOriginalBackupIfNeeded(); err != nil {
+	if err := r.createOriginalBackupIfNeeded(path); err != nil {
 		return fmt.Errorf("failed to create original backup: %w", err)
 	}
 
-	if err := r.createBackup(); err != nil {
+	if err := r.createBackup(path); err != nil {
 		return fmt.Errorf("failed to create backup: %w", err)
 	}
 
-	if err := r.fileSystem.Rename(tempFile, r.configPath); err != nil {
+	if err := r.fileSystem.Rename(tempFile, path); err != nil {
 		return fmt.Errorf("failed to atomically replace config file: %w", err)
 	}
 
-	r.logger.Infof("SSH config successfully updated: %s", r.configPath)
+	r.logger.Infof("SSH config successfully updated: %s", path)
 	return nil
 }
 
 // writeConfigToFile writes the SSH config content to the specified file
-func (r *Repository) writeConfigToFile(filePath string, cfg *ssh_config.Config) error {
+func (r *Repository) writeConfigToFile(filePath string, content []byte) error {
 	file, err := r.fileSystem.OpenFile(filePath, os.O_WRONLY|os.O_TRUNC, SSHConfigPerms)
 	if err != nil {
 		return fmt.Errorf("failed to open file for writing: %w", err)
@@ -95,8 +290,7 @@ func (r *Repository) writeConfigToFile(filePath string, cfg *ssh_config.Config)
 		}
 	}()
 
-	configContent := cfg.String()
-	if _, err := file.WriteString(configContent); err != nil {
+	if _, err := file.Write(content); err != nil {
 		return fmt.Errorf("failed to write config content: %w", err)
 	}
 
@@ -107,10 +301,10 @@ func (r *Repository) writeConfigToFile(filePath string, cfg *ssh_config.Config)
 	return nil
 }
 
-// createTempFile creates a temporary file in the specified directory
-func (r *Repository) createTempFile(dir string) (string, error) {
+// createTempFile creates a temporary file for the named config file in the specified directory
+func (r *Repository) createTempFile(dir, baseName string) (string, error) {
 	timestamp := time.Now().Format("20060102150405")
-	tempFileName := fmt.Sprintf("config%s%s", timestamp, TempSuffix)
+	tempFileName := fmt.Sprintf("%s%s%s", baseName, timestamp, TempSuffix)
 	tempFilePath := filepath.Join(dir, tempFileName)
 
 	// Create the temp file with explicit 0600 permissions
@@ -0,0 +1,230 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/adapters/data/settings"
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+// writeTestFile writes content to dir/name, creating parent directories as needed.
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	return path
+}
+
+func newTestRepository(t *testing.T, dir string) *Repository {
+	t.Helper()
+	return &Repository{
+		logger:          zap.NewNop().Sugar(),
+		configPath:      filepath.Join(dir, "config"),
+		fileSystem:      DefaultFileSystem{},
+		metadataManager: newMetadataManager(filepath.Join(dir, "metadata.json"), zap.NewNop().Sugar()),
+		backups:         settings.Default().Backup,
+		history:         &history{},
+	}
+}
+
+func TestListServersFollowsIncludes(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := writeTestFile(t, dir, "config", "Include config.d/*\n\nHost main\n    HostName 10.0.0.1\n")
+	workPath := writeTestFile(t, dir, "config.d/work", "Host work\n    HostName 10.0.0.2\n")
+	// An Include pointing back at the main config must not recurse forever.
+	writeTestFile(t, dir, "config.d/loop", "Include "+mainPath+"\n\nHost loop\n    HostName 10.0.0.3\n")
+	// Backups and temporary files left beside an included file are not part of the config.
+	for _, name := range []string{"work-1700000000000-lazyssh.backup", "work-1700000000000-lazyssh.backup.gz", "work.original.backup", "work20250101000000.tmp"} {
+		writeTestFile(t, dir, "config.d/"+name, "Host work\n    HostName 10.0.0.9\n")
+	}
+
+	r := newTestRepository(t, dir)
+	servers, err := r.ListServers()
+	if err != nil {
+		t.Fatalf("ListServers() error = %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, s := range servers {
+		got[s.Alias] = s.SourceFile
+	}
+	want := map[string]string{
+		"main": mainPath,
+		"work": workPath,
+		"loop": filepath.Join(dir, "config.d", "loop"),
+	}
+	if len(servers) != len(want) {
+		t.Fatalf("ListServers() returned %d servers, want %d: %v", len(servers), len(want), got)
+	}
+	for alias, path := range want {
+		if got[alias] != path {
+			t.Errorf("server %q SourceFile = %q, want %q", alias, got[alias], path)
+		}
+	}
+}
+
+func TestServerLines(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "config", "# servers\nUser admin\n\nHost web www\n\n    # note\n    HostName 10.0.0.1\nHost empty\nHost db\n    HostName 10.0.0.2\n")
+	r := newTestRepository(t, dir)
+
+	servers, err := r.ListServers()
+	if err != nil {
+		t.Fatalf("ListServers() error = %v", err)
+	}
+	want := map[string]int{"web": 4, "empty": 8, "db": 9}
+	for _, s := range servers {
+		if s.Line != want[s.Alias] {
+			t.Errorf("server %q Line = %d, want %d", s.Alias, s.Line, want[s.Alias])
+		}
+	}
+}
+
+func TestWritesAreRoutedToDefiningFile(t *testing.T) {
+	dir := t.TempDir()
+	mainContent := "Include config.d/*\n\nHost main\n    HostName 10.0.0.1\n"
+	mainPath := writeTestFile(t, dir, "config", mainContent)
+	workPath := writeTestFile(t, dir, "config.d/work", "Host work\n    HostName 10.0.0.2\n")
+
+	r := newTestRepository(t, dir)
+	work := domain.Server{Alias: "work", Host: "10.0.0.2"}
+	updated := work
+	updated.Host = "10.0.0.20"
+	if err := r.UpdateServer(work, updated); err != nil {
+		t.Fatalf("UpdateServer() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(workPath)
+	if !strings.Contains(string(data), "HostName 10.0.0.20") {
+		t.Errorf("included file not updated:\n%s", data)
+	}
+	data, _ = os.ReadFile(mainPath)
+	if string(data) != mainContent {
+		t.Errorf("main config modified by update of included host:\n%s", data)
+	}
+
+	backupDir := filepath.Join(dir, includeBackupDir)
+	backups, err := r.findBackupFiles(backupDir, "config.d_work")
+	if err != nil || len(backups) != 1 {
+		t.Errorf("expected one backup of included file, got %d (err=%v)", len(backups), err)
+	}
+	if _, err := os.Stat(filepath.Join(backupDir, "config.d_work"+OriginalBackupSuffix)); err != nil {
+		t.Errorf("expected original backup of included file: %v", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(workPath)); len(entries) != 1 {
+		t.Errorf("backups written beside the included file, where its Include glob reads them: %v", entries)
+	}
+	servers, err := r.ListServers()
+	if err != nil {
+		t.Fatalf("ListServers() error = %v", err)
+	}
+	if len(servers) != 2 {
+		t.Errorf("ListServers() after editing an included host returned %d servers, want main and work", len(servers))
+	}
+
+	if err := r.DeleteServer(updated); err != nil {
+		t.Fatalf("DeleteServer() error = %v", err)
+	}
+	data, _ = os.ReadFile(workPath)
+	if strings.Contains(string(data), "Host work") {
+		t.Errorf("host not removed from included file:\n%s", data)
+	}
+}
+
+func TestIsBackupOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		base     string
+		expected bool
+	}{
+		{"main config backup", "config-1700000000000-lazyssh.backup", "config", true},
+		{"included file backup", "work-1700000000000-lazyssh.backup", "work", true},
+		{"compressed backup", "config-1700000000000-lazyssh.backup.gz", "config", true},
+		{"backup of a different file sharing the prefix", "config-work-1700000000000-lazyssh.backup", "config", false},
+		{"original backup", "config.original.backup", "config", false},
+		{"missing timestamp", "config--lazyssh.backup", "config", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBackupOf(tt.file, tt.base); got != tt.expected {
+				t.Errorf("isBackupOf(%q, %q) = %v, want %v", tt.file, tt.base, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddServerToChosenFile(t *testing.T) {
+	dir := t.TempDir()
+	mainContent := "Include config.d/*\n"
+	mainPath := writeTestFile(t, dir, "config", mainContent)
+	teamPath := writeTestFile(t, dir, "config.d/team", "Host shared\n    HostName 10.0.0.2\n")
+
+	r := newTestRepository(t, dir)
+	files, err := r.ListConfigFiles()
+	if err != nil {
+		t.Fatalf("ListConfigFiles() error = %v", err)
+	}
+	if len(files) != 2 || files[0] != mainPath || files[1] != teamPath {
+		t.Fatalf("ListConfigFiles() = %v, want [%s %s]", files, mainPath, teamPath)
+	}
+
+	if last, _ := r.LastConfigFile(); last != mainPath {
+		t.Errorf("LastConfigFile() before any add = %q, want main config", last)
+	}
+
+	server := domain.Server{Alias: "new", Host: "10.0.0.3", Tags: []string{"team"}, SourceFile: teamPath}
+	if err := r.AddServer(server); err != nil {
+		t.Fatalf("AddServer() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(teamPath)
+	if !strings.Contains(string(data), "Host new") {
+		t.Errorf("server not written to chosen file:\n%s", data)
+	}
+	data, _ = os.ReadFile(mainPath)
+	if string(data) != mainContent {
+		t.Errorf("main config modified:\n%s", data)
+	}
+	if last, _ := r.LastConfigFile(); last != teamPath {
+		t.Errorf("LastConfigFile() = %q, want %q", last, teamPath)
+	}
+
+	// The remembered choice must not leak into the per-server metadata.
+	metadata, err := r.metadataManager.loadAll()
+	if err != nil {
+		t.Fatalf("loadAll() error = %v", err)
+	}
+	if _, ok := metadata[preferencesKey]; ok || len(metadata) != 1 {
+		t.Errorf("loadAll() = %v, want only the new server", metadata)
+	}
+
+	outside := domain.Server{Alias: "other", Host: "10.0.0.4", SourceFile: filepath.Join(dir, "elsewhere")}
+	if err := r.AddServer(outside); err == nil {
+		t.Errorf("AddServer() to a file outside the config succeeded, want error")
+	}
+}
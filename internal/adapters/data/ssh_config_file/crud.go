@@ -23,52 +23,18 @@ import (
 )
 
 const (
-	MaxBackups         = 10
-	TempSuffix         = ".tmp"
-	BackupSuffix       = "lazyssh.backup"
-	SSHConfigPerms     = 0o600
-	OriginalBackupName = "config.original.backup"
+	TempSuffix           = ".tmp"
+	BackupSuffix         = "lazyssh.backup"
+	CompressedSuffix     = ".gz"
+	SSHConfigPerms       = 0o600
+	OriginalBackupSuffix = ".original.backup"
+	SystemConfigDir      = "/etc/ssh"
 )
 
-// filterServers filters servers based on the query string.
-func (r *Repository) filterServers(servers []domain.Server, query string) []domain.Server {
-	query = strings.ToLower(query)
-	filtered := make([]domain.Server, 0)
-
-	for _, server := range servers {
-		if r.matchesQuery(server, query) {
-			filtered = append(filtered, server)
-		}
-	}
-
-	return filtered
-}
-
-// matchesQuery checks if any field of the server matches the query string.
-func (r *Repository) matchesQuery(server domain.Server, query string) bool {
-	fields := []string{
-		strings.ToLower(server.Host),
-		strings.ToLower(server.User),
-	}
-	for _, tag := range server.Tags {
-		fields = append(fields, strings.ToLower(tag))
-	}
-	for _, alias := range server.Aliases {
-		fields = append(fields, strings.ToLower(alias))
-	}
-
-	for _, field := range fields {
-		if strings.Contains(field, query) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// serverExists checks if a server with the given alias already exists in the config.
-func (r *Repository) serverExists(cfg *ssh_config.Config, alias string) bool {
-	return r.findHostByAlias(cfg, alias) != nil
+// serverExists checks if a server with the given alias already exists in any of the loaded config files.
+func (r *Repository) serverExists(files []*configFile, alias string) bool {
+	file, _ := r.findConfigFileByAlias(files, alias)
+	return file != nil
 }
 
 // findHostByAlias finds a host by its alias in the SSH config.
@@ -15,7 +15,11 @@
 package ssh_config_file
 
 import (
+	"os"
+	"strings"
 	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
 )
 
 func TestConvertCLIForwardToConfigFormat(t *testing.T) {
@@ -145,3 +149,225 @@ func TestConvertConfigForwardToCLIFormat(t *testing.T) {
 		})
 	}
 }
+
+// matchBlockConfig has Match blocks directly after the Host blocks the parser attaches them to.
+const matchBlockConfig = `Host alpha
+    HostName 10.0.0.1
+    User admin
+
+Match host *.prod exec "test -f ~/.vpn" # only on the VPN
+    User deploy
+    ProxyJump bastion
+    IdentityFile ~/.ssh/prod
+
+Host beta
+    HostName 10.0.0.2
+
+Match user root
+  IdentityFile ~/.ssh/root
+  ForwardAgent no
+`
+
+// extractMatchBlocks returns the text of every Match block: the Match line and all
+// lines up to the next Host or Match line.
+func extractMatchBlocks(content string) []string {
+	blocks := make([]string, 0)
+	var current *strings.Builder
+	for _, line := range strings.SplitAfter(content, "\n") {
+		keyword := strings.ToLower(strings.Fields(line + " x")[0])
+		if keyword == "host" || keyword == "match" {
+			if current != nil {
+				blocks = append(blocks, current.String())
+				current = nil
+			}
+			if keyword == "match" {
+				current = &strings.Builder{}
+			}
+		}
+		if current != nil {
+			current.WriteString(line)
+		}
+	}
+	if current != nil {
+		blocks = append(blocks, current.String())
+	}
+	return blocks
+}
+
+func assertMatchBlocksUnchanged(t *testing.T, path string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	want := extractMatchBlocks(matchBlockConfig)
+	got := extractMatchBlocks(string(data))
+	if len(got) != len(want) {
+		t.Fatalf("found %d Match blocks, want %d:\n%s", len(got), len(want), data)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Match block %d changed:\ngot:\n%q\nwant:\n%q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestMatchBlocksSurviveCRUD(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(r *Repository) error
+		check func(t *testing.T, content string)
+	}{
+		{
+			name:  "list only",
+			apply: func(r *Repository) error { return nil },
+		},
+		{
+			name: "add server",
+			apply: func(r *Repository) error {
+				return r.AddServer(domain.Server{Alias: "gamma", Host: "10.0.0.3", User: "ops"})
+			},
+			check: func(t *testing.T, content string) {
+				if !strings.HasSuffix(content, "Host gamma    #Added by lazyssh\n    HostName 10.0.0.3\n    User ops\n") {
+					t.Errorf("new host not appended after the last Match block:\n%s", content)
+				}
+			},
+		},
+		{
+			name: "update server before Match block",
+			apply: func(r *Repository) error {
+				old := domain.Server{Alias: "alpha", Host: "10.0.0.1", User: "admin", Port: 22}
+				updated := old
+				updated.Alias = "alpha2"
+				updated.User = "root"
+				updated.ProxyJump = "jump"
+				updated.ForwardAgent = "yes"
+				return r.UpdateServer(old, updated)
+			},
+			check: func(t *testing.T, content string) {
+				alpha := content[:strings.Index(content, "Match host")]
+				for _, want := range []string{"Host alpha2", "User root", "ProxyJump jump", "ForwardAgent yes"} {
+					if !strings.Contains(alpha, want) {
+						t.Errorf("updated host is missing %q:\n%s", want, alpha)
+					}
+				}
+			},
+		},
+		{
+			name: "update server clearing keys set in Match block",
+			apply: func(r *Repository) error {
+				old := domain.Server{Alias: "beta", Host: "10.0.0.2", Port: 22}
+				updated := old
+				updated.Host = "10.0.0.20"
+				return r.UpdateServer(old, updated)
+			},
+			check: func(t *testing.T, content string) {
+				if !strings.Contains(content, "Host beta\n    HostName 10.0.0.20\n") {
+					t.Errorf("beta not updated:\n%s", content)
+				}
+			},
+		},
+		{
+			name: "delete server before Match block",
+			apply: func(r *Repository) error {
+				return r.DeleteServer(domain.Server{Alias: "alpha"})
+			},
+			check: func(t *testing.T, content string) {
+				if strings.Contains(content, "alpha") {
+					t.Errorf("alpha not deleted:\n%s", content)
+				}
+			},
+		},
+		{
+			name: "delete last server",
+			apply: func(r *Repository) error {
+				return r.DeleteServer(domain.Server{Alias: "beta"})
+			},
+			check: func(t *testing.T, content string) {
+				if strings.Contains(content, "beta") || !strings.Contains(content, "Host alpha") {
+					t.Errorf("unexpected result:\n%s", content)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := writeTestFile(t, dir, "config", matchBlockConfig)
+			r := newTestRepository(t, dir)
+
+			if err := tt.apply(r); err != nil {
+				t.Fatalf("apply error = %v", err)
+			}
+			assertMatchBlocksUnchanged(t, path)
+			if tt.check != nil {
+				data, _ := os.ReadFile(path)
+				tt.check(t, string(data))
+			}
+
+			// Settings of a Match block must never be attributed to the Host before it.
+			servers, err := r.ListServers()
+			if err != nil {
+				t.Fatalf("ListServers() error = %v", err)
+			}
+			for _, s := range servers {
+				if s.User == "deploy" || s.ProxyJump == "bastion" || len(s.IdentityFiles) > 0 {
+					t.Errorf("server %q picked up Match block settings: %+v", s.Alias, s)
+				}
+			}
+		})
+	}
+}
+
+func TestListMatchBlocks(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "config", matchBlockConfig)
+	r := newTestRepository(t, dir)
+
+	blocks, err := r.ListMatchBlocks()
+	if err != nil {
+		t.Fatalf("ListMatchBlocks() error = %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("ListMatchBlocks() returned %d blocks, want 2", len(blocks))
+	}
+
+	first := blocks[0]
+	if first.SourceFile != path || first.Line != 5 {
+		t.Errorf("first block location = %s:%d, want %s:5", first.SourceFile, first.Line, path)
+	}
+	want := []domain.MatchCriterion{
+		{Keyword: "host", Argument: "*.prod"},
+		{Keyword: "exec", Argument: "test -f ~/.vpn"},
+	}
+	if len(first.Criteria) != len(want) {
+		t.Fatalf("criteria = %+v, want %+v", first.Criteria, want)
+	}
+	for i := range want {
+		if first.Criteria[i] != want[i] {
+			t.Errorf("criterion %d = %+v, want %+v", i, first.Criteria[i], want[i])
+		}
+	}
+	if got := strings.Join(first.Settings, "; "); got != "User deploy; ProxyJump bastion; IdentityFile ~/.ssh/prod" {
+		t.Errorf("settings = %q", got)
+	}
+}
+
+func TestNotesAndFields(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "config", "Host web\n    HostName 10.0.0.1\n")
+	r := newTestRepository(t, dir)
+
+	web := domain.Server{Alias: "web", Host: "10.0.0.1", Port: 22}
+	annotated := web
+	annotated.Notes = "Restart with `systemctl restart nginx`"
+	annotated.Fields = map[string]string{"owner": "alice", "rack": "B12"}
+	if err := r.UpdateServer(web, annotated); err != nil {
+		t.Fatalf("UpdateServer() error = %v", err)
+	}
+
+	if got := findServer(t, r, "web"); got.Notes != annotated.Notes || got.Fields["rack"] != "B12" {
+		t.Errorf("notes and fields were not kept: %+v", got)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// diffLine is one line of a line-based diff: op is ' ' (unchanged), '-' (removed) or '+' (added).
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// unifiedDiff renders the changes from from to to as a unified diff.
+// It returns an empty string when both contents are equal.
+func unifiedDiff(fromName, toName string, from, to []byte) string {
+	lines := diffLines(splitLines(string(from)), splitLines(string(to)))
+
+	// fromLine[i] and toLine[i] are the number of lines of each side before lines[i].
+	fromLine := make([]int, len(lines)+1)
+	toLine := make([]int, len(lines)+1)
+	for i, l := range lines {
+		fromLine[i+1], toLine[i+1] = fromLine[i], toLine[i]
+		if l.op != '+' {
+			fromLine[i+1]++
+		}
+		if l.op != '-' {
+			toLine[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	for start := 0; start < len(lines); {
+		first := start
+		for first < len(lines) && lines[first].op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+
+		// Changes separated by less than two contexts' worth of lines share a hunk.
+		end := first
+		for {
+			for end < len(lines) && lines[end].op != ' ' {
+				end++
+			}
+			next := end
+			for next < len(lines) && lines[next].op == ' ' {
+				next++
+			}
+			if next == len(lines) || next-end > 2*diffContext {
+				break
+			}
+			end = next
+		}
+
+		hunkStart := max(first-diffContext, start)
+		hunkEnd := min(end+diffContext, len(lines))
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(fromLine[hunkStart], fromLine[hunkEnd]-fromLine[hunkStart]),
+			hunkRange(toLine[hunkStart], toLine[hunkEnd]-toLine[hunkStart]))
+		for _, l := range lines[hunkStart:hunkEnd] {
+			sb.WriteByte(l.op)
+			sb.WriteString(l.text)
+			sb.WriteByte('\n')
+		}
+		start = hunkEnd
+	}
+	return sb.String()
+}
+
+// hunkRange formats the line range of one side of a hunk, given the lines before it and its length.
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// diffLines computes a minimal line diff of a and b from their longest common subsequence.
+// The common prefix and suffix are trimmed first, which keeps typical config edits cheap.
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	am, bm := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is the length of the longest common subsequence of am[i:] and bm[j:].
+	lcs := make([][]int, len(am)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bm)+1)
+	}
+	for i := len(am) - 1; i >= 0; i-- {
+		for j := len(bm) - 1; j >= 0; j-- {
+			if am[i] == bm[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		lines = append(lines, diffLine{' ', l})
+	}
+	i, j := 0, 0
+	for i < len(am) || j < len(bm) {
+		switch {
+		case i < len(am) && j < len(bm) && am[i] == bm[j]:
+			lines = append(lines, diffLine{' ', am[i]})
+			i++
+			j++
+		case i < len(am) && (j == len(bm) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', am[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', bm[j]})
+			j++
+		}
+	}
+	for _, l := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{' ', l})
+	}
+	return lines
+}
+
+// splitLines splits s into lines without their line endings.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,56 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     string
+	}{
+		{"equal", "Host a\n", "Host a\n", ""},
+		{
+			"changed line",
+			"Host a\n    HostName 1\n    Port 22\n",
+			"Host a\n    HostName 2\n    Port 22\n",
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n Host a\n-    HostName 1\n+    HostName 2\n     Port 22\n",
+		},
+		{
+			"added block",
+			"Host a\n",
+			"Host a\n\nHost b\n",
+			"--- old\n+++ new\n@@ -1,1 +1,3 @@\n Host a\n+\n+Host b\n",
+		},
+		{
+			"from empty",
+			"",
+			"Host a\n",
+			"--- old\n+++ new\n@@ -0,0 +1,1 @@\n+Host a\n",
+		},
+		{
+			"separate hunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			"x\n2\n3\n4\n5\n6\n7\n8\n9\ny\n",
+			"--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+y\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := unifiedDiff("old", "new", []byte(tt.from), []byte(tt.to)); got != tt.want {
+			t.Errorf("%s: unifiedDiff() =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
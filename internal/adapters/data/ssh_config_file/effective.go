@@ -0,0 +1,407 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/kevinburke/ssh_config"
+)
+
+// cumulativeKeys are options for which ssh keeps every obtained value instead of only the first one.
+var cumulativeKeys = map[string]bool{
+	"identityfile":    true,
+	"certificatefile": true,
+	"localforward":    true,
+	"remoteforward":   true,
+	"dynamicforward":  true,
+	"sendenv":         true,
+}
+
+// leadingKeys are listed first in the effective configuration, in this order.
+var leadingKeys = []string{"hostname", "user", "port", "identityfile"}
+
+// sshDefaults are the OpenSSH defaults shown for options that no block sets.
+// HostName and User are filled in per server since they depend on the alias and local user.
+var sshDefaults = []struct {
+	key   string
+	value string
+}{
+	{"HostName", ""},
+	{"User", ""},
+	{"Port", "22"},
+	{"IdentityFile", "~/.ssh/id_rsa"},
+	{"IdentityFile", "~/.ssh/id_ecdsa"},
+	{"IdentityFile", "~/.ssh/id_ecdsa_sk"},
+	{"IdentityFile", "~/.ssh/id_ed25519"},
+	{"IdentityFile", "~/.ssh/id_ed25519_sk"},
+	{"AddressFamily", "any"},
+	{"ConnectionAttempts", "1"},
+	{"RequestTTY", "auto"},
+	{"ServerAliveInterval", "0"},
+	{"ServerAliveCountMax", "3"},
+	{"TCPKeepAlive", "yes"},
+	{"Compression", "no"},
+	{"BatchMode", "no"},
+	{"ControlMaster", "no"},
+	{"PubkeyAuthentication", "yes"},
+	{"PasswordAuthentication", "yes"},
+	{"IdentitiesOnly", "no"},
+	{"AddKeysToAgent", "no"},
+	{"ForwardAgent", "no"},
+	{"ForwardX11", "no"},
+	{"StrictHostKeyChecking", "ask"},
+	{"CheckHostIP", "no"},
+	{"UserKnownHostsFile", "~/.ssh/known_hosts ~/.ssh/known_hosts2"},
+	{"LogLevel", "INFO"},
+}
+
+// effectiveIndex is what resolving effective settings needs from one load of the config
+// files. Built once, it lets every server be resolved without globbing Include patterns
+// again or visiting the Host blocks of other servers, which would make resolving all
+// servers quadratic in their number.
+type effectiveIndex struct {
+	repo     *Repository
+	root     *configFile
+	files    map[string]*fileIndex
+	own      map[string]*ssh_config.Host // first Host block naming each alias
+	includes map[*ssh_config.Include][]string
+}
+
+// fileIndex splits the blocks of a file into those that name only concrete aliases, which
+// apply just to those aliases, and all others, which must be matched against each alias.
+type fileIndex struct {
+	file     *configFile
+	general  []int
+	concrete map[string][]int
+}
+
+// effectiveResolver walks the config the way ssh reads it and collects the settings
+// that apply to a single alias.
+type effectiveResolver struct {
+	index    *effectiveIndex
+	alias    string
+	own      *ssh_config.Host
+	settings []domain.EffectiveSetting
+	obtained map[string]bool
+	visiting map[string]bool
+}
+
+// ResolveEffective returns the configuration ssh would apply to each of aliases, with the
+// origin of every value. Blocks are evaluated in file order, Include directives are followed
+// where they appear, and the first obtained value wins, except for cumulative options such as
+// IdentityFile. Options that no block sets are reported with their OpenSSH default. The config
+// is loaded and indexed once for all aliases.
+func (r *Repository) ResolveEffective(aliases []string) (map[string][]domain.EffectiveSetting, error) {
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	index := r.newEffectiveIndex(files)
+	effective := make(map[string][]domain.EffectiveSetting, len(aliases))
+	for _, alias := range aliases {
+		if _, ok := effective[alias]; !ok {
+			effective[alias] = index.resolve(alias)
+		}
+	}
+	return effective, nil
+}
+
+func (r *Repository) newEffectiveIndex(files []*configFile) *effectiveIndex {
+	index := &effectiveIndex{
+		repo:     r,
+		files:    make(map[string]*fileIndex, len(files)),
+		own:      make(map[string]*ssh_config.Host),
+		includes: make(map[*ssh_config.Include][]string),
+	}
+	if len(files) > 0 {
+		index.root = files[0]
+	}
+	for _, file := range files {
+		fi := &fileIndex{file: file, concrete: make(map[string][]int)}
+		for i, host := range file.cfg.Hosts {
+			concrete := !host.Implicit && len(host.Patterns) > 0
+			for _, pattern := range host.Patterns {
+				name := pattern.String()
+				if _, ok := index.own[name]; !ok {
+					index.own[name] = host
+				}
+				concrete = concrete && !strings.ContainsAny(name, "!*?[]")
+			}
+			if !concrete {
+				fi.general = append(fi.general, i)
+				continue
+			}
+			for _, pattern := range host.Patterns {
+				blocks := fi.concrete[pattern.String()]
+				if len(blocks) == 0 || blocks[len(blocks)-1] != i {
+					fi.concrete[pattern.String()] = append(blocks, i)
+				}
+			}
+		}
+		index.files[filepath.Clean(file.path)] = fi
+	}
+	return index
+}
+
+func (index *effectiveIndex) resolve(alias string) []domain.EffectiveSetting {
+	if index.root == nil {
+		return nil
+	}
+	e := &effectiveResolver{
+		index:    index,
+		alias:    alias,
+		own:      index.own[alias],
+		obtained: make(map[string]bool),
+		visiting: make(map[string]bool),
+	}
+	e.walk(index.root)
+	e.addDefaults()
+	return e.ordered()
+}
+
+// include returns the files an Include directive pulls in, globbing its patterns only once.
+func (index *effectiveIndex) include(inc *ssh_config.Include) []string {
+	paths, ok := index.includes[inc]
+	if !ok {
+		paths = index.repo.resolveInclude(includePatterns(inc))
+		index.includes[inc] = paths
+	}
+	return paths
+}
+
+// walk evaluates the blocks of file that may apply to the alias, descending into Include
+// directives of matching blocks. Blocks naming only other aliases are skipped.
+func (e *effectiveResolver) walk(file *configFile) {
+	key := filepath.Clean(file.path)
+	if e.visiting[key] {
+		return
+	}
+	e.visiting[key] = true
+	defer delete(e.visiting, key)
+
+	fi := e.index.files[key]
+	for _, i := range mergeIndexes(fi.general, fi.concrete[e.alias]) {
+		host := file.cfg.Hosts[i]
+		origin := domain.OriginHostBlock
+		if host == e.own {
+			origin = domain.OriginOwnBlock
+		}
+		block := "Host " + hostPatterns(host)
+		active := host.Matches(e.alias)
+		nodes := host.Nodes
+		switch {
+		case isMatchBlock(host):
+			// splitMatchBlocks put the Match line first in a block of its own.
+			match := nodes[0].(*ssh_config.KV)
+			origin = domain.OriginMatchBlock
+			block = "Match " + match.Value
+			active = e.matchApplies(match.Value)
+			nodes = nodes[1:]
+		case host.Implicit:
+			block = "global settings"
+		}
+
+		for _, node := range nodes {
+			switch n := node.(type) {
+			case *ssh_config.KV:
+				if active {
+					e.add(n, origin, block, file.path)
+				}
+			case *ssh_config.Include:
+				if !active {
+					continue
+				}
+				for _, path := range e.index.include(n) {
+					if child, ok := e.index.files[filepath.Clean(path)]; ok {
+						e.walk(child.file)
+					}
+				}
+			}
+		}
+	}
+}
+
+// mergeIndexes merges two ascending lists of block indexes.
+func mergeIndexes(a, b []int) []int {
+	merged := make([]int, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if a[0] < b[0] {
+			merged, a = append(merged, a[0]), a[1:]
+		} else {
+			merged, b = append(merged, b[0]), b[1:]
+		}
+	}
+	return append(append(merged, a...), b...)
+}
+
+// add records a setting unless an earlier block already provided it.
+func (e *effectiveResolver) add(kv *ssh_config.KV, origin domain.SettingOrigin, block, path string) {
+	key := strings.ToLower(kv.Key)
+	if key == "" || key == "host" || key == "include" {
+		return
+	}
+	if e.obtained[key] && !cumulativeKeys[key] {
+		return
+	}
+	e.obtained[key] = true
+	e.settings = append(e.settings, domain.EffectiveSetting{
+		Key:    kv.Key,
+		Value:  kv.Value,
+		Origin: origin,
+		Block:  block,
+		File:   path,
+		Line:   kv.Position.Line,
+	})
+}
+
+// addDefaults appends OpenSSH defaults for the options no block has set.
+func (e *effectiveResolver) addDefaults() {
+	for _, def := range sshDefaults {
+		key := strings.ToLower(def.key)
+		if e.obtained[key] {
+			continue
+		}
+		value := def.value
+		switch key {
+		case "hostname":
+			value = e.alias
+		case "user":
+			value = localUser()
+		}
+		e.settings = append(e.settings, domain.EffectiveSetting{
+			Key:    def.key,
+			Value:  value,
+			Origin: domain.OriginDefault,
+		})
+	}
+}
+
+// ordered returns the settings with the most important options first and the
+// remaining ones in the order they were obtained.
+func (e *effectiveResolver) ordered() []domain.EffectiveSetting {
+	result := make([]domain.EffectiveSetting, 0, len(e.settings))
+	for _, key := range leadingKeys {
+		for _, s := range e.settings {
+			if strings.EqualFold(s.Key, key) {
+				result = append(result, s)
+			}
+		}
+	}
+	for _, s := range e.settings {
+		if !isLeadingKey(s.Key) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// value returns the first obtained value of key, or "" when no block has set it yet.
+func (e *effectiveResolver) value(key string) string {
+	for _, s := range e.settings {
+		if strings.EqualFold(s.Key, key) {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// matchApplies evaluates the criteria of a Match line. Only criteria that can be decided
+// without running ssh are supported: all, host, originalhost, user and localuser, each
+// optionally negated with "!". Blocks using other criteria (exec, canonical, final,
+// localnetwork, tagged) are treated as not matching.
+func (e *effectiveResolver) matchApplies(value string) bool {
+	criteria := parseMatchCriteria(value)
+	if len(criteria) == 0 {
+		return false
+	}
+	for _, c := range criteria {
+		if c.Keyword != "all" && c.Argument == "" {
+			return false
+		}
+
+		var matched bool
+		switch c.Keyword {
+		case "all":
+			matched = true
+		case "host":
+			host := e.value("hostname")
+			if host == "" {
+				host = e.alias
+			}
+			matched = patternListMatches(c.Argument, host)
+		case "originalhost":
+			matched = patternListMatches(c.Argument, e.alias)
+		case "user":
+			u := e.value("user")
+			if u == "" {
+				u = localUser()
+			}
+			matched = patternListMatches(c.Argument, u)
+		case "localuser":
+			matched = patternListMatches(c.Argument, localUser())
+		default:
+			return false
+		}
+		if matched == c.Negated {
+			return false
+		}
+	}
+	return true
+}
+
+// patternListMatches reports whether value matches a comma-separated ssh pattern list.
+func patternListMatches(list, value string) bool {
+	host := &ssh_config.Host{}
+	for _, p := range strings.Split(list, ",") {
+		pattern, err := ssh_config.NewPattern(strings.TrimSpace(p))
+		if err != nil {
+			continue
+		}
+		host.Patterns = append(host.Patterns, pattern)
+	}
+	return host.Matches(value)
+}
+
+// hostPatterns renders the patterns of a Host line.
+func hostPatterns(host *ssh_config.Host) string {
+	patterns := make([]string, 0, len(host.Patterns))
+	for _, p := range host.Patterns {
+		patterns = append(patterns, p.String())
+	}
+	return strings.Join(patterns, " ")
+}
+
+func isLeadingKey(key string) bool {
+	for _, k := range leadingKeys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// localUser returns the name of the user running lazyssh, which ssh uses when no User is set.
+func localUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,121 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestResolveEffective(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := writeTestFile(t, dir, "config", `Include config.d/*
+
+Host web.prod.internal
+    HostName 10.0.0.1
+    Port 2222
+
+Host *.prod.internal
+    User deploy
+    Port 22
+    ProxyJump bastion
+    IdentityFile ~/.ssh/prod
+
+Match originalhost web.* user deploy
+    ForwardAgent yes
+
+Match exec "true"
+    Compression yes
+`)
+	teamPath := writeTestFile(t, dir, "config.d/team", "Host *\n    ServerAliveInterval 30\n")
+
+	writeTestFile(t, dir, "config.d/other", "Host db.prod.internal api.prod.internal\n    User dba\n")
+
+	r := newTestRepository(t, dir)
+	effective, err := r.ResolveEffective([]string{"web.prod.internal", "db.prod.internal"})
+	if err != nil {
+		t.Fatalf("ResolveEffective() error = %v", err)
+	}
+	settings := effective["web.prod.internal"]
+
+	type want struct {
+		value  string
+		origin domain.SettingOrigin
+		file   string
+		line   int
+	}
+	tests := map[string]want{
+		"HostName":            {"10.0.0.1", domain.OriginOwnBlock, mainPath, 4},
+		"Port":                {"2222", domain.OriginOwnBlock, mainPath, 5},
+		"User":                {"deploy", domain.OriginHostBlock, mainPath, 8},
+		"ProxyJump":           {"bastion", domain.OriginHostBlock, mainPath, 10},
+		"IdentityFile":        {"~/.ssh/prod", domain.OriginHostBlock, mainPath, 11},
+		"ForwardAgent":        {"yes", domain.OriginMatchBlock, mainPath, 14},
+		"ServerAliveInterval": {"30", domain.OriginHostBlock, teamPath, 2},
+		"Compression":         {"no", domain.OriginDefault, "", 0},
+	}
+
+	got := make(map[string][]domain.EffectiveSetting)
+	for _, s := range settings {
+		got[s.Key] = append(got[s.Key], s)
+	}
+	for key, w := range tests {
+		values := got[key]
+		if len(values) != 1 {
+			t.Errorf("%s: got %d values %v, want 1", key, len(values), values)
+			continue
+		}
+		s := values[0]
+		if s.Value != w.value || s.Origin != w.origin || s.File != w.file || s.Line != w.line {
+			t.Errorf("%s = %+v, want value %q origin %d at %s:%d", key, s, w.value, w.origin, w.file, w.line)
+		}
+	}
+	if settings[0].Key != "HostName" {
+		t.Errorf("first setting = %q, want HostName", settings[0].Key)
+	}
+	user := ""
+	for _, s := range effective["db.prod.internal"] {
+		if s.Key == "User" {
+			user = s.Value
+		}
+	}
+	if user != "dba" {
+		t.Errorf("db.prod.internal User = %q, want dba", user)
+	}
+}
+
+func TestMatchApplies(t *testing.T) {
+	e := &effectiveResolver{alias: "db1", obtained: map[string]bool{}}
+	tests := []struct {
+		criteria string
+		want     bool
+	}{
+		{"all", true},
+		{"host db*", true},
+		{"host web*,db*", true},
+		{"host *,!db1", false},
+		{"!host web*", true},
+		{"originalhost db1 all", true},
+		{"exec \"test -f /tmp/x\"", false},
+		{"host", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := e.matchApplies(tt.criteria); got != tt.want {
+			t.Errorf("matchApplies(%q) = %v, want %v", tt.criteria, got, tt.want)
+		}
+	}
+}
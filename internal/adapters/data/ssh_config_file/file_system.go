@@ -17,6 +17,7 @@ package ssh_config_file
 import (
 	"io"
 	"os"
+	"path/filepath"
 )
 
 // FileSystem interface for file operations to enable testing.
@@ -29,7 +30,9 @@ type FileSystem interface {
 	Rename(file string, path string) error
 	Chmod(path string, perms os.FileMode) error
 	OpenFile(path string, i int, perms os.FileMode) (*os.File, error)
+	MkdirAll(path string, perms os.FileMode) error
 	ReadDir(dir string) ([]os.DirEntry, error)
+	Glob(pattern string) ([]string, error)
 }
 
 // DefaultFileSystem implements FileSystem using standard os package.
@@ -70,6 +73,14 @@ func (fs DefaultFileSystem) OpenFile(path string, i int, perms os.FileMode) (*os
 	return os.OpenFile(path, i, perms)
 }
 
+func (fs DefaultFileSystem) MkdirAll(path string, perms os.FileMode) error {
+	return os.MkdirAll(path, perms)
+}
+
 func (fs DefaultFileSystem) ReadDir(dir string) ([]os.DirEntry, error) {
 	return os.ReadDir(dir)
 }
+
+func (fs DefaultFileSystem) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
@@ -0,0 +1,309 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MaxHistory is the number of changes kept for undo in a session.
+const MaxHistory = 50
+
+// fileSnapshot is the content of a file at one point in time; a missing file has nil content.
+type fileSnapshot struct {
+	path    string
+	content []byte
+}
+
+// historyEntry records the files a single change modified, before and after the change.
+type historyEntry struct {
+	description string
+	before      []fileSnapshot
+	after       []fileSnapshot
+}
+
+// history is the in-session undo/redo stack of config and metadata changes.
+type history struct {
+	mu   sync.Mutex
+	undo []historyEntry
+	redo []historyEntry
+}
+
+// tracked runs mutate and records the files it changed so the change can be undone.
+// Every file lazyssh may write (the config, its includes and the metadata file) is
+// snapshotted before and after; only the files whose content changed are kept.
+func (r *Repository) tracked(description string, mutate func() error) error {
+	var paths []string
+	var before, after []fileSnapshot
+	err := r.ownWrite(func() error {
+		paths = r.trackedPaths()
+		before = r.snapshot(paths)
+		r.operation = description
+		defer func() { r.operation = "" }()
+		err := mutate()
+		after = r.snapshot(paths)
+		return err
+	})
+	if before == nil {
+		return err
+	}
+
+	entry := historyEntry{description: description}
+	for i := range paths {
+		if !bytes.Equal(before[i].content, after[i].content) {
+			entry.before = append(entry.before, before[i])
+			entry.after = append(entry.after, after[i])
+		}
+	}
+	if len(entry.after) == 0 {
+		return err
+	}
+
+	r.history.mu.Lock()
+	defer r.history.mu.Unlock()
+	r.history.undo = append(r.history.undo, entry)
+	if len(r.history.undo) > MaxHistory {
+		r.history.undo = r.history.undo[len(r.history.undo)-MaxHistory:]
+	}
+	r.history.redo = nil
+	return err
+}
+
+// Undo reverts the most recent change and returns its description.
+func (r *Repository) Undo() (string, error) {
+	r.history.mu.Lock()
+	defer r.history.mu.Unlock()
+
+	if len(r.history.undo) == 0 {
+		return "", errors.New("nothing to undo")
+	}
+	entry := r.history.undo[len(r.history.undo)-1]
+	err := r.ownWrite(func() error {
+		r.operation = "undo " + entry.description
+		defer func() { r.operation = "" }()
+		return r.replaceSnapshots(entry.after, entry.before)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to undo %s: %w", entry.description, err)
+	}
+	r.history.undo = r.history.undo[:len(r.history.undo)-1]
+	r.history.redo = append(r.history.redo, entry)
+	return entry.description, nil
+}
+
+// Redo reapplies the most recently undone change and returns its description.
+func (r *Repository) Redo() (string, error) {
+	r.history.mu.Lock()
+	defer r.history.mu.Unlock()
+
+	if len(r.history.redo) == 0 {
+		return "", errors.New("nothing to redo")
+	}
+	entry := r.history.redo[len(r.history.redo)-1]
+	err := r.ownWrite(func() error {
+		r.operation = "redo " + entry.description
+		defer func() { r.operation = "" }()
+		return r.replaceSnapshots(entry.before, entry.after)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to redo %s: %w", entry.description, err)
+	}
+	r.history.redo = r.history.redo[:len(r.history.redo)-1]
+	r.history.undo = append(r.history.undo, entry)
+	return entry.description, nil
+}
+
+// replaceSnapshots writes target over the files, provided they still hold expected.
+// If a file was modified outside lazyssh in the meantime the history no longer applies
+// to it, so nothing is written and the history is dropped. The metadata file is the
+// exception: connecting to a server updates it without going through the history, so
+// its changes are merged field by field and only conflicting fields count as modified.
+func (r *Repository) replaceSnapshots(expected, target []fileSnapshot) error {
+	contents := make([][]byte, len(target))
+	for i, snap := range expected {
+		current := r.readSnapshot(snap.path)
+		contents[i] = target[i].content
+		if bytes.Equal(current.content, snap.content) {
+			continue
+		}
+		if snap.path == r.metadataManager.filePath {
+			if merged, err := mergeMetadata(current.content, snap.content, target[i].content); err == nil {
+				contents[i] = merged
+				continue
+			}
+		}
+		r.history.undo = nil
+		r.history.redo = nil
+		return fmt.Errorf("%s was changed outside lazyssh; history cleared", snap.path)
+	}
+
+	for i, snap := range target {
+		var err error
+		if snap.path == r.metadataManager.filePath {
+			err = r.metadataManager.writeRaw(contents[i])
+		} else {
+			err = r.writeConfig(snap.path, contents[i])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeMetadata applies the change from expected to target on top of current, entry by
+// entry and field by field. It fails if current changed a field the change also touches.
+func mergeMetadata(current, expected, target []byte) ([]byte, error) {
+	cur, err := decodeMetadataFields(current)
+	if err != nil {
+		return nil, err
+	}
+	exp, err := decodeMetadataFields(expected)
+	if err != nil {
+		return nil, err
+	}
+	tgt, err := decodeMetadataFields(target)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool)
+	for _, m := range []map[string]map[string]json.RawMessage{cur, exp, tgt} {
+		for key := range m {
+			keys[key] = true
+		}
+	}
+	for key := range keys {
+		fields := make(map[string]bool)
+		for _, m := range []map[string]map[string]json.RawMessage{cur, exp, tgt} {
+			for field := range m[key] {
+				fields[field] = true
+			}
+		}
+		for field := range fields {
+			c, e, t := cur[key][field], exp[key][field], tgt[key][field]
+			switch {
+			case bytes.Equal(e, t), bytes.Equal(c, t):
+			case bytes.Equal(c, e):
+				if cur[key] == nil {
+					cur[key] = make(map[string]json.RawMessage)
+				}
+				if t == nil {
+					delete(cur[key], field)
+				} else {
+					cur[key][field] = t
+				}
+			default:
+				return nil, fmt.Errorf("conflicting change to %s.%s", key, field)
+			}
+		}
+		switch {
+		case len(cur[key]) == 0 && tgt[key] == nil:
+			delete(cur, key)
+		case cur[key] == nil && exp[key] == nil:
+			cur[key] = tgt[key]
+		}
+	}
+	return encodeMetadataFields(cur)
+}
+
+// decodeMetadataFields decodes metadata of any version into raw fields per entry, with the
+// preferences under preferencesKey; empty content is an empty map.
+func decodeMetadataFields(data []byte) (map[string]map[string]json.RawMessage, error) {
+	doc, err := parseMetadataDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	entries := doc.Servers
+	if len(doc.Preferences) > 0 {
+		entries[preferencesKey] = doc.Preferences
+	}
+	fields := make(map[string]map[string]json.RawMessage, len(entries))
+	for key, value := range entries {
+		entry := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(value, &entry); err != nil {
+			return nil, err
+		}
+		fields[key] = entry
+	}
+	return fields, nil
+}
+
+// encodeMetadataFields is the inverse of decodeMetadataFields, at metadataVersion.
+func encodeMetadataFields(fields map[string]map[string]json.RawMessage) ([]byte, error) {
+	doc := metadataDocument{Version: metadataVersion, Servers: make(map[string]json.RawMessage, len(fields))}
+	for key, entry := range fields {
+		value, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		if key == preferencesKey {
+			doc.Preferences = value
+		} else {
+			doc.Servers[key] = value
+		}
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// trackedPaths lists every file a change may touch.
+func (r *Repository) trackedPaths() []string {
+	paths := make([]string, 0)
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		paths = append(paths, r.configPath)
+	}
+	for _, file := range files {
+		paths = append(paths, file.path)
+	}
+	return append(paths, r.metadataManager.filePath)
+}
+
+func (r *Repository) snapshot(paths []string) []fileSnapshot {
+	snapshots := make([]fileSnapshot, len(paths))
+	for i, path := range paths {
+		snapshots[i] = r.readSnapshot(path)
+	}
+	return snapshots
+}
+
+func (r *Repository) readSnapshot(path string) fileSnapshot {
+	snap := fileSnapshot{path: path}
+	if content, ok := r.preview[path]; ok {
+		snap.content = content
+		return snap
+	}
+	file, err := r.fileSystem.Open(path)
+	if err != nil {
+		return snap
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			r.logger.Warnf("failed to close %s: %v", path, cerr)
+		}
+	}()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		r.logger.Warnf("failed to read %s for history: %v", path, err)
+		return snap
+	}
+	snap.content = content
+	return snap
+}
@@ -0,0 +1,172 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestUndoRedo(t *testing.T) {
+	dir := t.TempDir()
+	content := "Host web\n    HostName 10.0.0.1\n"
+	path := writeTestFile(t, dir, "config", content)
+	metadataPath := filepath.Join(dir, "metadata.json")
+	r := newTestRepository(t, dir)
+
+	readFile := func(p string) string {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+
+	if _, err := r.Undo(); err == nil {
+		t.Errorf("Undo() with empty history succeeded, want error")
+	}
+
+	if err := r.AddServer(domain.Server{Alias: "db", Host: "10.0.0.2"}); err != nil {
+		t.Fatalf("AddServer() error = %v", err)
+	}
+	added, addedMetadata := readFile(path), readFile(metadataPath)
+	if err := r.SetPinned("db", true); err != nil {
+		t.Fatalf("SetPinned() error = %v", err)
+	}
+	pinned := readFile(metadataPath)
+
+	tests := []struct {
+		op       func() (string, error)
+		wantDesc string
+		config   string
+		metadata string
+	}{
+		{r.Undo, "pin db", added, addedMetadata},
+		{r.Undo, "add server db", content, ""},
+		{r.Redo, "add server db", added, addedMetadata},
+		{r.Redo, "pin db", added, pinned},
+	}
+	for i, tt := range tests {
+		desc, err := tt.op()
+		if err != nil {
+			t.Fatalf("step %d: error = %v", i, err)
+		}
+		if desc != tt.wantDesc {
+			t.Errorf("step %d: description = %q, want %q", i, desc, tt.wantDesc)
+		}
+		if got := readFile(path); got != tt.config {
+			t.Errorf("step %d: config = %q, want %q", i, got, tt.config)
+		}
+		if got := readFile(metadataPath); got != tt.metadata {
+			t.Errorf("step %d: metadata = %q, want %q", i, got, tt.metadata)
+		}
+	}
+
+	if _, err := r.Redo(); err == nil {
+		t.Errorf("Redo() past the newest change succeeded, want error")
+	}
+}
+
+func TestUndoAfterExternalChange(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "config", "Host web\n    HostName 10.0.0.1\n")
+	r := newTestRepository(t, dir)
+
+	if err := r.DeleteServer(domain.Server{Alias: "web", SourceFile: path}); err != nil {
+		t.Fatalf("DeleteServer() error = %v", err)
+	}
+	external := "Host other\n    HostName 10.0.0.9\n"
+	if err := os.WriteFile(path, []byte(external), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Undo(); err == nil {
+		t.Fatalf("Undo() over an externally modified file succeeded, want error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != external {
+		t.Errorf("Undo() overwrote the external change:\n%s", data)
+	}
+	if _, err := r.Undo(); err == nil {
+		t.Errorf("history was not cleared after an external change")
+	}
+}
+
+func TestDescribeUpdate(t *testing.T) {
+	web := domain.Server{Alias: "web", Host: "10.0.0.1", Tags: []string{"prod"}}
+	tagged := web
+	tagged.Tags = []string{"prod", "eu"}
+	moved := web
+	moved.Host = "10.0.0.2"
+	renamed := web
+	renamed.Alias = "www"
+	annotated := web
+	annotated.Notes = "Runbook: https://wiki/web"
+	annotated.Fields = map[string]string{"owner": "alice"}
+
+	tests := []struct {
+		newServer domain.Server
+		want      string
+	}{
+		{tagged, "edit tags of web"},
+		{moved, "edit server web"},
+		{renamed, "rename server web to www"},
+		{annotated, "edit notes of web"},
+	}
+	for _, tt := range tests {
+		if got := describeUpdate(web, tt.newServer); got != tt.want {
+			t.Errorf("describeUpdate() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestUndoAfterConnect(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "config", "Host web\n    HostName 10.0.0.1\n")
+	r := newTestRepository(t, dir)
+
+	if err := r.SetPinned("web", true); err != nil {
+		t.Fatalf("SetPinned() error = %v", err)
+	}
+	if err := r.RecordSSH("web"); err != nil {
+		t.Fatalf("RecordSSH() error = %v", err)
+	}
+	if _, err := r.Undo(); err != nil {
+		t.Fatalf("Undo() after connecting error = %v", err)
+	}
+	metadata, err := r.metadataManager.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if web := metadata["web"]; web.PinnedAt != "" || web.SSHCount != 1 {
+		t.Errorf("metadata after undo = %+v, want unpinned with the connection kept", web)
+	}
+
+	if _, err := r.Redo(); err != nil {
+		t.Fatalf("Redo() error = %v", err)
+	}
+	metadata, prefs, hash, _ := r.metadataManager.loadForUpdate()
+	web := metadata["web"]
+	web.PinnedAt = "2000-01-01T00:00:00Z"
+	metadata["web"] = web
+	if err := r.metadataManager.save(metadata, prefs, hash); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Undo(); err == nil {
+		t.Errorf("Undo() over a conflicting metadata change succeeded, want error")
+	}
+}
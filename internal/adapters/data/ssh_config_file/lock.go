@@ -0,0 +1,102 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// lockTimeout is how long a change waits for another lazyssh process to release its locks.
+var lockTimeout = 5 * time.Second
+
+// lockRetryInterval is how often a held lock is retried.
+const lockRetryInterval = 50 * time.Millisecond
+
+// ErrLocked is returned when another process holds the lock of a config or metadata file for too long.
+var ErrLocked = errors.New("locked by another lazyssh process")
+
+// lockFiles takes the advisory locks of paths, in sorted order so that two processes
+// locking overlapping sets cannot deadlock. The returned function releases them.
+// Lock files live in the lazyssh directory rather than beside the locked files, so
+// nothing is added to ~/.ssh.
+func (r *Repository) lockFiles(paths []string) (func(), error) {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	held := make([]*os.File, 0, len(sorted))
+	unlock := func() {
+		for i := len(held) - 1; i >= 0; i-- {
+			if err := unlockFile(held[i]); err != nil {
+				r.logger.Warnf("failed to unlock %s: %v", held[i].Name(), err)
+			}
+			if err := held[i].Close(); err != nil {
+				r.logger.Warnf("failed to close lock file %s: %v", held[i].Name(), err)
+			}
+		}
+	}
+
+	for _, path := range sorted {
+		lock, err := r.lockFile(path)
+		if err != nil {
+			unlock()
+			return nil, err
+		}
+		held = append(held, lock)
+	}
+	return unlock, nil
+}
+
+// lockFile takes the advisory lock of a single file, retrying until lockTimeout.
+func (r *Repository) lockFile(path string) (*os.File, error) {
+	lockPath := r.lockPath(path)
+	if err := r.fileSystem.MkdirAll(filepath.Dir(lockPath), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	lock, err := r.fileSystem.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := tryLockFile(lock)
+		if err != nil {
+			_ = lock.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if locked {
+			return lock, nil
+		}
+		if time.Now().After(deadline) {
+			_ = lock.Close()
+			return nil, fmt.Errorf("%s is %w", path, ErrLocked)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// lockPath returns the lock file guarding path.
+func (r *Repository) lockPath(path string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(path)))
+	name := fmt.Sprintf("%s-%s.lock", filepath.Base(path), hex.EncodeToString(sum[:6]))
+	return filepath.Join(filepath.Dir(r.metadataManager.filePath), "locks", name)
+}
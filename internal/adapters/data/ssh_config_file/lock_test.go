@@ -0,0 +1,178 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestStaleWritesAreRejected(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "config", "Host web\n    HostName 10.0.0.1\n")
+	r := newTestRepository(t, dir)
+
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		t.Fatalf("loadConfigFiles() error = %v", err)
+	}
+	external := "Host web\n    HostName 10.0.0.9\n"
+	if err := os.WriteFile(path, []byte(external), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.saveConfig(files[0]); !errors.Is(err, ErrConfigChanged) {
+		t.Errorf("saveConfig() of a stale config error = %v, want ErrConfigChanged", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != external {
+		t.Errorf("stale save overwrote the outside change:\n%s", data)
+	}
+
+	metadata, prefs, hash, err := r.metadataManager.loadForUpdate()
+	if err != nil {
+		t.Fatalf("loadForUpdate() error = %v", err)
+	}
+	if err := r.metadataManager.setPinned("web", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.metadataManager.save(metadata, prefs, hash); !errors.Is(err, ErrMetadataChanged) {
+		t.Errorf("save() of stale metadata error = %v, want ErrMetadataChanged", err)
+	}
+	if metadata, _ := r.metadataManager.loadAll(); metadata["web"].PinnedAt == "" {
+		t.Errorf("stale metadata save overwrote the outside change")
+	}
+}
+
+func TestConcurrentWriters(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "config", "Host web\n    HostName 10.0.0.1\n")
+	// Two repositories on the same files behave like two lazyssh processes:
+	// only the advisory locks keep them from interleaving.
+	writers := []*Repository{newTestRepository(t, dir), newTestRepository(t, dir)}
+
+	const perWriter = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, len(writers)*perWriter*2)
+	for w, r := range writers {
+		wg.Add(1)
+		go func(w int, r *Repository) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				alias := fmt.Sprintf("host-%d-%d", w, i)
+				errs <- r.AddServer(domain.Server{Alias: alias, Host: "10.1.0.1"})
+				errs <- r.SetPinned(alias, true)
+			}
+		}(w, r)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent write error = %v", err)
+		}
+	}
+
+	servers, err := writers[0].ListServers()
+	if err != nil {
+		t.Fatalf("ListServers() error = %v", err)
+	}
+	pinned := make(map[string]bool)
+	for _, server := range servers {
+		pinned[server.Alias] = !server.PinnedAt.IsZero()
+	}
+	for w := range writers {
+		for i := 0; i < perWriter; i++ {
+			alias := fmt.Sprintf("host-%d-%d", w, i)
+			if isPinned, ok := pinned[alias]; !ok {
+				t.Errorf("%s lost from the config", alias)
+			} else if !isPinned {
+				t.Errorf("pin of %s lost from the metadata", alias)
+			}
+		}
+	}
+}
+
+func TestLockTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { lockTimeout = timeout }(lockTimeout)
+	lockTimeout = 100 * time.Millisecond
+
+	dir := t.TempDir()
+	writeTestFile(t, dir, "config", "Host web\n    HostName 10.0.0.1\n")
+	holder, waiter := newTestRepository(t, dir), newTestRepository(t, dir)
+
+	unlock, err := holder.lockFiles(holder.trackedPaths())
+	if err != nil {
+		t.Fatalf("lockFiles() error = %v", err)
+	}
+	if err := waiter.AddServer(domain.Server{Alias: "db", Host: "10.0.0.2"}); !errors.Is(err, ErrLocked) {
+		t.Errorf("AddServer() while locked error = %v, want ErrLocked", err)
+	}
+	unlock()
+	if err := waiter.AddServer(domain.Server{Alias: "db", Host: "10.0.0.2"}); err != nil {
+		t.Errorf("AddServer() after unlock error = %v", err)
+	}
+}
+
+// recordingFileSystem records the directories and files the repository creates.
+type recordingFileSystem struct {
+	DefaultFileSystem
+	mu    sync.Mutex
+	paths []string
+}
+
+func (fs *recordingFileSystem) MkdirAll(path string, perms os.FileMode) error {
+	fs.record(path)
+	return fs.DefaultFileSystem.MkdirAll(path, perms)
+}
+
+func (fs *recordingFileSystem) OpenFile(path string, flag int, perms os.FileMode) (*os.File, error) {
+	fs.record(path)
+	return fs.DefaultFileSystem.OpenFile(path, flag, perms)
+}
+
+func (fs *recordingFileSystem) record(path string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.paths = append(fs.paths, path)
+}
+
+func TestLocksGoThroughFileSystem(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "config", "Host web\n    HostName 10.0.0.1\n")
+	fs := &recordingFileSystem{}
+	r := newTestRepository(t, dir)
+	r.fileSystem = fs
+
+	unlock, err := r.lockFiles(r.trackedPaths())
+	if err != nil {
+		t.Fatalf("lockFiles() error = %v", err)
+	}
+	unlock()
+
+	lockDir := filepath.Join(dir, "locks")
+	want := []string{lockDir, r.lockPath(r.configPath)}
+	for _, path := range want {
+		if !slices.Contains(fs.paths, path) {
+			t.Errorf("%s was not created through the file system, got %v", path, fs.paths)
+		}
+	}
+}
@@ -24,8 +24,10 @@ import (
 )
 
 // toDomainServer converts ssh_config.Config to a slice of domain.Server.
-func (r *Repository) toDomainServer(cfg *ssh_config.Config) []domain.Server {
+// sourceFile records which config file the servers were read from.
+func (r *Repository) toDomainServer(cfg *ssh_config.Config, sourceFile string) []domain.Server {
 	servers := make([]domain.Server, 0, len(cfg.Hosts))
+	lines := hostLines(cfg)
 	for _, host := range cfg.Hosts {
 
 		aliases := make([]string, 0, len(host.Patterns))
@@ -46,6 +48,8 @@ func (r *Repository) toDomainServer(cfg *ssh_config.Config) []domain.Server {
 			Aliases:       aliases,
 			Port:          22,
 			IdentityFiles: []string{},
+			SourceFile:    sourceFile,
+			Line:          lines[host],
 		}
 
 		for _, node := range host.Nodes {
@@ -56,6 +60,9 @@ func (r *Repository) toDomainServer(cfg *ssh_config.Config) []domain.Server {
 
 			r.mapKVToServer(&server, kvNode)
 		}
+		if r.annotationsInConfig() {
+			readAnnotations(host, &server)
+		}
 
 		servers = append(servers, server)
 	}
@@ -63,6 +70,26 @@ func (r *Repository) toDomainServer(cfg *ssh_config.Config) []domain.Server {
 	return servers
 }
 
+// hostLines returns the line of the Host line of each block. The parser keeps no position for
+// Host lines, so it is the line before the first node of the block, or the line after the last
+// node of the previous block when the block is empty.
+func hostLines(cfg *ssh_config.Config) map[*ssh_config.Host]int {
+	lines := make(map[*ssh_config.Host]int, len(cfg.Hosts))
+	last := 0
+	for _, host := range cfg.Hosts {
+		if len(host.Nodes) == 0 {
+			if !host.Implicit {
+				last++
+				lines[host] = last
+			}
+			continue
+		}
+		lines[host] = host.Nodes[0].Pos().Line - 1
+		last = host.Nodes[len(host.Nodes)-1].Pos().Line
+	}
+	return lines
+}
+
 // mapKVToServer maps an ssh_config.KV node to the corresponding fields in domain.Server.
 func (r *Repository) mapKVToServer(server *domain.Server, kvNode *ssh_config.KV) {
 	key := strings.ToLower(kvNode.Key)
@@ -297,7 +324,18 @@ func (r *Repository) mergeMetadata(servers []domain.Server, metadata map[string]
 		servers[i].LastSeen = time.Time{}
 
 		if meta, exists := metadata[server.Alias]; exists {
-			servers[i].Tags = meta.Tags
+			// With metadata in the config, the file only fills in hosts not annotated yet;
+			// they are written as comments the next time the server is saved.
+			if !r.annotationsInConfig() || !hasAnnotations(server) {
+				servers[i].Tags = meta.Tags
+				servers[i].Notes = meta.Notes
+				servers[i].Fields = meta.Fields
+				if meta.PinnedAt != "" {
+					if pinnedAt, err := time.Parse(time.RFC3339, meta.PinnedAt); err == nil {
+						servers[i].PinnedAt = pinnedAt
+					}
+				}
+			}
 			servers[i].SSHCount = meta.SSHCount
 
 			if meta.LastSeen != "" {
@@ -305,12 +343,6 @@ func (r *Repository) mergeMetadata(servers []domain.Server, metadata map[string]
 					servers[i].LastSeen = lastSeen
 				}
 			}
-
-			if meta.PinnedAt != "" {
-				if pinnedAt, err := time.Parse(time.RFC3339, meta.PinnedAt); err == nil {
-					servers[i].PinnedAt = pinnedAt
-				}
-			}
 		}
 	}
 	return servers
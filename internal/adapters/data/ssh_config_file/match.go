@@ -0,0 +1,150 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/kevinburke/ssh_config"
+)
+
+// matchCriteriaWithoutArgument are Match keywords that take no argument.
+var matchCriteriaWithoutArgument = map[string]bool{
+	"all":       true,
+	"canonical": true,
+	"final":     true,
+}
+
+// splitMatchBlocks moves every Match line, together with the lines that follow it, out of
+// the Host the parser attached it to and into a Host of its own. The new Host is implicit,
+// so it prints no "Host" line and the file is written back byte for byte, while edits and
+// deletes of the preceding Host no longer reach into the Match block.
+func splitMatchBlocks(cfg *ssh_config.Config) {
+	hosts := make([]*ssh_config.Host, 0, len(cfg.Hosts))
+	for _, host := range cfg.Hosts {
+		current := host
+		nodes := host.Nodes
+		start := 0
+		for i, node := range nodes {
+			if i == 0 && isMatchBlock(host) {
+				continue
+			}
+			kv, ok := node.(*ssh_config.KV)
+			if !ok || !strings.EqualFold(kv.Key, "match") {
+				continue
+			}
+			current.Nodes = nodes[start:i:i]
+			hosts = append(hosts, current)
+			current = &ssh_config.Host{Implicit: true}
+			start = i
+		}
+		current.Nodes = nodes[start:]
+		hosts = append(hosts, current)
+	}
+	cfg.Hosts = hosts
+}
+
+// isMatchBlock reports whether host holds a Match block split out by splitMatchBlocks.
+func isMatchBlock(host *ssh_config.Host) bool {
+	if !host.Implicit || len(host.Nodes) == 0 {
+		return false
+	}
+	kv, ok := host.Nodes[0].(*ssh_config.KV)
+	return ok && strings.EqualFold(kv.Key, "match")
+}
+
+// ListMatchBlocks returns the Match blocks of the main config and its includes, in file order.
+// Match blocks are read-only in lazyssh; they are listed so their criteria and settings can be inspected.
+func (r *Repository) ListMatchBlocks() ([]domain.MatchBlock, error) {
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	blocks := make([]domain.MatchBlock, 0)
+	for _, file := range files {
+		for _, host := range file.cfg.Hosts {
+			if !isMatchBlock(host) {
+				continue
+			}
+			match := host.Nodes[0].(*ssh_config.KV)
+			block := domain.MatchBlock{
+				Criteria:   parseMatchCriteria(match.Value),
+				Settings:   make([]string, 0),
+				SourceFile: file.path,
+				Line:       match.Position.Line,
+			}
+			for _, node := range host.Nodes[1:] {
+				switch n := node.(type) {
+				case *ssh_config.KV:
+					block.Settings = append(block.Settings, strings.TrimSpace(n.Key+" "+n.Value))
+				case *ssh_config.Include:
+					block.Settings = append(block.Settings, strings.TrimSpace(n.String()))
+				}
+			}
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+// parseMatchCriteria splits the arguments of a Match line into criteria. Arguments may be
+// double-quoted to contain spaces, as exec commands usually are.
+func parseMatchCriteria(value string) []domain.MatchCriterion {
+	tokens := splitQuoted(value)
+	criteria := make([]domain.MatchCriterion, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		criterion := domain.MatchCriterion{Keyword: strings.ToLower(tokens[i])}
+		if strings.HasPrefix(criterion.Keyword, "!") {
+			criterion.Negated = true
+			criterion.Keyword = strings.TrimPrefix(criterion.Keyword, "!")
+		}
+		if !matchCriteriaWithoutArgument[criterion.Keyword] && i+1 < len(tokens) {
+			i++
+			criterion.Argument = tokens[i]
+		}
+		criteria = append(criteria, criterion)
+	}
+	return criteria
+}
+
+// splitQuoted splits s on whitespace, keeping double-quoted sections together without their quotes.
+func splitQuoted(s string) []string {
+	tokens := make([]string, 0)
+	var current strings.Builder
+	inQuotes, hasToken := false, false
+	for _, c := range s {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+			hasToken = true
+		case !inQuotes && (c == ' ' || c == '\t'):
+			if hasToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				hasToken = false
+			}
+		default:
+			current.WriteRune(c)
+			hasToken = true
+		}
+	}
+	if hasToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
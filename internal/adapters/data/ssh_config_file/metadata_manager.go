@@ -15,26 +15,145 @@
 package ssh_config_file
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/fsutil"
 	"go.uber.org/zap"
 )
 
 type ServerMetadata struct {
-	Tags     []string `json:"tags,omitempty"`
-	LastSeen string   `json:"last_seen,omitempty"`
-	PinnedAt string   `json:"pinned_at,omitempty"`
-	SSHCount int      `json:"ssh_count,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Notes    string            `json:"notes,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	LastSeen string            `json:"last_seen,omitempty"`
+	PinnedAt string            `json:"pinned_at,omitempty"`
+	SSHCount int               `json:"ssh_count,omitempty"`
+	// Fingerprint identifies the server the entry belongs to independently of its alias,
+	// so the entry can be matched to the server again after a rename outside lazyssh.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// metadataPreferences holds lazyssh-wide settings stored next to the per-server entries.
+type metadataPreferences struct {
+	LastConfigFile string `json:"last_config_file,omitempty"`
+}
+
+// ErrMetadataChanged is returned when the metadata file changed on disk after lazyssh read it.
+var ErrMetadataChanged = errors.New("metadata changed on disk")
+
+// ErrMetadataVersion is returned for a metadata file written by a newer lazyssh.
+var ErrMetadataVersion = errors.New("unsupported metadata version")
+
+// metadataVersion is the schema version of the metadata file written by this lazyssh.
+const metadataVersion = 2
+
+// preferencesKey is the entry that held metadataPreferences in the unversioned (version 1)
+// metadata file, and that stands for them when the history merges metadata changes.
+// '@' is not allowed in aliases, so it can never collide with a Host.
+const preferencesKey = "@preferences"
+
+// lastGoodSuffix names the copy of the metadata file kept from before the latest write.
+// It is read when the metadata file itself can no longer be decoded.
+const lastGoodSuffix = ".bak"
+
+// metadataDocument is the layout of the metadata file since version 2. Entries are kept
+// raw so that the history can merge them field by field.
+type metadataDocument struct {
+	Version     int                        `json:"version"`
+	Preferences json.RawMessage            `json:"preferences,omitempty"`
+	Servers     map[string]json.RawMessage `json:"servers"`
+}
+
+// metadataMigrations upgrade the top-level fields of a metadata file by one version:
+// metadataMigrations[v] turns version v into version v+1.
+var metadataMigrations = map[int]func(map[string]json.RawMessage) (map[string]json.RawMessage, error){
+	1: migrateFlatMetadata,
+}
+
+// migrateFlatMetadata moves the entries of the unversioned flat map, keyed by alias with
+// the preferences under preferencesKey, into the servers and preferences fields.
+func migrateFlatMetadata(raw map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	servers := make(map[string]json.RawMessage, len(raw))
+	for key, value := range raw {
+		if key != preferencesKey {
+			servers[key] = value
+		}
+	}
+	data, err := json.Marshal(servers)
+	if err != nil {
+		return nil, err
+	}
+	migrated := map[string]json.RawMessage{"servers": data}
+	if prefs, ok := raw[preferencesKey]; ok {
+		migrated["preferences"] = prefs
+	}
+	return migrated, nil
+}
+
+// parseMetadataDocument decodes metadata of any known version, migrating it to
+// metadataVersion. Empty data is an empty document.
+func parseMetadataDocument(data []byte) (metadataDocument, error) {
+	doc := metadataDocument{Version: metadataVersion, Servers: make(map[string]json.RawMessage)}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return doc, nil
+	}
+
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return doc, err
+	}
+	version := metadataFileVersion(raw)
+	if version > metadataVersion {
+		return doc, fmt.Errorf("%w %d (this lazyssh supports up to %d)", ErrMetadataVersion, version, metadataVersion)
+	}
+	for ; version < metadataVersion; version++ {
+		migrate, ok := metadataMigrations[version]
+		if !ok {
+			return doc, fmt.Errorf("%w %d", ErrMetadataVersion, version)
+		}
+		migrated, err := migrate(raw)
+		if err != nil {
+			return doc, fmt.Errorf("migrate metadata from version %d: %w", version, err)
+		}
+		raw = migrated
+	}
+
+	doc.Preferences = raw["preferences"]
+	if servers, ok := raw["servers"]; ok {
+		if err := json.Unmarshal(servers, &doc.Servers); err != nil {
+			return doc, err
+		}
+	}
+	if doc.Servers == nil {
+		doc.Servers = make(map[string]json.RawMessage)
+	}
+	return doc, nil
+}
+
+// metadataFileVersion returns the schema version of a decoded metadata file. Files written
+// before versioning have no numeric "version" field and are version 1.
+func metadataFileVersion(raw map[string]json.RawMessage) int {
+	var version int
+	if err := json.Unmarshal(raw["version"], &version); err != nil {
+		return 1
+	}
+	return version
 }
 
 type metadataManager struct {
 	filePath string
 	logger   *zap.SugaredLogger
+	// localOnly keeps only the connection history in the file, because tags, pins, notes
+	// and fields are stored as comments in the SSH config (settings.MetadataStoreConfig).
+	localOnly bool
+	preview   pendingWrites // shared with the repository while a change is previewed
 }
 
 func newMetadataManager(filePath string, logger *zap.SugaredLogger) *metadataManager {
@@ -42,50 +161,179 @@ func newMetadataManager(filePath string, logger *zap.SugaredLogger) *metadataMan
 }
 
 func (m *metadataManager) loadAll() (map[string]ServerMetadata, error) {
-	metadata := make(map[string]ServerMetadata)
+	metadata, _, err := m.load()
+	return metadata, err
+}
+
+// load reads the metadata file and splits it into per-server entries and preferences.
+func (m *metadataManager) load() (map[string]ServerMetadata, metadataPreferences, error) {
+	metadata, prefs, _, err := m.loadForUpdate()
+	return metadata, prefs, err
+}
+
+// loadForUpdate is load that also returns the hash of the content read, for save to check.
+// If the metadata file cannot be decoded, the last good copy is used instead; the hash is
+// still that of the broken file, so the next save replaces it.
+func (m *metadataManager) loadForUpdate() (map[string]ServerMetadata, metadataPreferences, string, error) {
+	data, err := m.read(m.filePath)
+	if err != nil {
+		return nil, metadataPreferences{}, "", err
+	}
+	metadata, prefs, err := m.decode(data)
+	if err != nil && !errors.Is(err, ErrMetadataVersion) {
+		metadata, prefs, err = m.recover(err)
+	}
+	return metadata, prefs, contentHash(data), err
+}
+
+// recover decodes the last good copy of the metadata file, or returns cause if there is none.
+func (m *metadataManager) recover(cause error) (map[string]ServerMetadata, metadataPreferences, error) {
+	lastGood := m.filePath + lastGoodSuffix
+	data, err := m.read(lastGood)
+	if err != nil || data == nil {
+		return nil, metadataPreferences{}, cause
+	}
+	metadata, prefs, err := m.decode(data)
+	if err != nil {
+		return nil, metadataPreferences{}, cause
+	}
+	m.logger.Warnw("metadata file is corrupt, using the last good copy", "path", m.filePath, "copy", lastGood, "error", cause)
+	return metadata, prefs, nil
+}
 
-	if _, err := os.Stat(m.filePath); os.IsNotExist(err) {
-		return metadata, nil
+// read returns the content of path, or nil if it does not exist.
+func (m *metadataManager) read(path string) ([]byte, error) {
+	if content, ok := m.preview[path]; ok {
+		return content, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read metadata '%s': %w", path, err)
 	}
+	return data, nil
+}
+
+func (m *metadataManager) decode(data []byte) (map[string]ServerMetadata, metadataPreferences, error) {
+	var prefs metadataPreferences
 
-	data, err := os.ReadFile(m.filePath)
+	doc, err := parseMetadataDocument(data)
 	if err != nil {
-		return nil, fmt.Errorf("read metadata '%s': %w", m.filePath, err)
+		return nil, prefs, fmt.Errorf("parse metadata JSON '%s': %w", m.filePath, err)
 	}
 
-	if len(data) == 0 {
-		return metadata, nil
+	if len(doc.Preferences) > 0 {
+		if err := json.Unmarshal(doc.Preferences, &prefs); err != nil {
+			return nil, prefs, fmt.Errorf("parse metadata preferences '%s': %w", m.filePath, err)
+		}
 	}
 
-	if err := json.Unmarshal(data, &metadata); err != nil {
-		return nil, fmt.Errorf("parse metadata JSON '%s': %w", m.filePath, err)
+	metadata := make(map[string]ServerMetadata, len(doc.Servers))
+	for alias, value := range doc.Servers {
+		var meta ServerMetadata
+		if err := json.Unmarshal(value, &meta); err != nil {
+			return nil, prefs, fmt.Errorf("parse metadata JSON '%s': %w", m.filePath, err)
+		}
+		metadata[alias] = meta
 	}
 
-	return metadata, nil
+	return metadata, prefs, nil
 }
 
-func (m *metadataManager) saveAll(metadata map[string]ServerMetadata) error {
-	if err := m.ensureDirectory(); err != nil {
-		m.logger.Errorw("failed to ensure metadata directory", "path", m.filePath, "error", err)
+// save writes per-server entries and preferences back to the metadata file. expectedHash is
+// the hash returned by loadForUpdate: if the file changed on disk since, the write is
+// rejected with ErrMetadataChanged rather than silently discarding the other change.
+func (m *metadataManager) save(metadata map[string]ServerMetadata, prefs metadataPreferences, expectedHash string) error {
+	current, err := m.read(m.filePath)
+	if err != nil {
+		return err
+	}
+	if contentHash(current) != expectedHash {
+		return fmt.Errorf("%w: %s", ErrMetadataChanged, m.filePath)
+	}
 
-		return fmt.Errorf("ensure metadata directory for '%s': %w", m.filePath, err)
+	doc := metadataDocument{Version: metadataVersion, Servers: make(map[string]json.RawMessage, len(metadata))}
+	for alias, meta := range metadata {
+		value, err := json.Marshal(meta)
+		if err != nil {
+			return fmt.Errorf("marshal metadata for '%s': %w", m.filePath, err)
+		}
+		doc.Servers[alias] = value
+	}
+	if prefs != (metadataPreferences{}) {
+		if doc.Preferences, err = json.Marshal(prefs); err != nil {
+			return fmt.Errorf("marshal metadata for '%s': %w", m.filePath, err)
+		}
 	}
 
-	data, err := json.MarshalIndent(metadata, "", "  ")
+	data, err := json.MarshalIndent(doc, "", "  ")
 	if err != nil {
 		m.logger.Errorw("failed to marshal metadata", "path", m.filePath, "error", err)
 		return fmt.Errorf("marshal metadata for '%s': %w", m.filePath, err)
 	}
 
-	if err := os.WriteFile(m.filePath, data, 0o600); err != nil {
+	if err := m.write(current, data); err != nil {
 		m.logger.Errorw("failed to write metadata file", "path", m.filePath, "error", err)
+		return err
+	}
+	return nil
+}
+
+// writeRaw replaces the metadata file with data, as recorded by the undo history.
+func (m *metadataManager) writeRaw(data []byte) error {
+	current, err := m.read(m.filePath)
+	if err != nil {
+		return err
+	}
+	return m.write(current, data)
+}
+
+// write replaces the metadata file, whose content is current, with data. If current is
+// valid metadata it is kept as the last good copy first.
+func (m *metadataManager) write(current, data []byte) error {
+	if m.preview != nil {
+		m.preview[m.filePath] = data
+		return nil
+	}
+	if err := m.ensureDirectory(); err != nil {
+		return fmt.Errorf("ensure metadata directory for '%s': %w", m.filePath, err)
+	}
+	if current != nil {
+		if _, _, err := m.decode(current); err == nil {
+			if err := fsutil.WriteFileAtomic(m.filePath+lastGoodSuffix, current); err != nil {
+				return fmt.Errorf("write last good metadata copy: %w", err)
+			}
+		}
+	}
+	if err := fsutil.WriteFileAtomic(m.filePath, data); err != nil {
 		return fmt.Errorf("write metadata '%s': %w", m.filePath, err)
 	}
 	return nil
 }
 
+func (m *metadataManager) lastConfigFile() (string, error) {
+	_, prefs, err := m.load()
+	if err != nil {
+		return "", fmt.Errorf("load metadata: %w", err)
+	}
+	return prefs.LastConfigFile, nil
+}
+
+func (m *metadataManager) setLastConfigFile(path string) error {
+	metadata, prefs, hash, err := m.loadForUpdate()
+	if err != nil {
+		m.logger.Errorw("failed to load metadata in setLastConfigFile", "path", m.filePath, "config_file", path, "error", err)
+		return fmt.Errorf("load metadata: %w", err)
+	}
+
+	prefs.LastConfigFile = path
+	return m.save(metadata, prefs, hash)
+}
+
 func (m *metadataManager) updateServer(server domain.Server, oldAlias string) error {
-	metadata, err := m.loadAll()
+	metadata, prefs, hash, err := m.loadForUpdate()
 	if err != nil {
 		m.logger.Errorw("failed to load metadata in updateServer", "path", m.filePath, "alias", server.Alias, "old_alias", oldAlias, "error", err)
 		return fmt.Errorf("load metadata: %w", err)
@@ -103,6 +351,8 @@ func (m *metadataManager) updateServer(server domain.Server, oldAlias string) er
 	merged := existing
 
 	merged.Tags = server.Tags
+	merged.Notes = server.Notes
+	merged.Fields = server.Fields
 
 	if !server.LastSeen.IsZero() {
 		merged.LastSeen = server.LastSeen.Format(time.RFC3339)
@@ -115,24 +365,29 @@ func (m *metadataManager) updateServer(server domain.Server, oldAlias string) er
 	if server.SSHCount > 0 {
 		merged.SSHCount = server.SSHCount
 	}
+	merged.Fingerprint = serverFingerprint(server)
+
+	if m.localOnly {
+		merged.Tags, merged.Notes, merged.Fields, merged.PinnedAt = nil, "", nil, ""
+	}
 
 	metadata[server.Alias] = merged
-	return m.saveAll(metadata)
+	return m.save(metadata, prefs, hash)
 }
 
 func (m *metadataManager) deleteServer(alias string) error {
-	metadata, err := m.loadAll()
+	metadata, prefs, hash, err := m.loadForUpdate()
 	if err != nil {
 		m.logger.Errorw("failed to load metadata in deleteServer", "path", m.filePath, "alias", alias, "error", err)
 		return fmt.Errorf("load metadata: %w", err)
 	}
 
 	delete(metadata, alias)
-	return m.saveAll(metadata)
+	return m.save(metadata, prefs, hash)
 }
 
 func (m *metadataManager) setPinned(alias string, pinned bool) error {
-	metadata, err := m.loadAll()
+	metadata, prefs, hash, err := m.loadForUpdate()
 	if err != nil {
 		m.logger.Errorw("failed to load metadata in setPinned", "path", m.filePath, "alias", alias, "pinned", pinned, "error", err)
 		return fmt.Errorf("load metadata: %w", err)
@@ -146,11 +401,11 @@ func (m *metadataManager) setPinned(alias string, pinned bool) error {
 	}
 
 	metadata[alias] = meta
-	return m.saveAll(metadata)
+	return m.save(metadata, prefs, hash)
 }
 
 func (m *metadataManager) recordSSH(alias string) error {
-	metadata, err := m.loadAll()
+	metadata, prefs, hash, err := m.loadForUpdate()
 	if err != nil {
 		m.logger.Errorw("failed to load metadata in recordSSH", "path", m.filePath, "alias", alias, "error", err)
 		return fmt.Errorf("load metadata: %w", err)
@@ -161,7 +416,7 @@ func (m *metadataManager) recordSSH(alias string) error {
 	meta.SSHCount++
 
 	metadata[alias] = meta
-	return m.saveAll(metadata)
+	return m.save(metadata, prefs, hash)
 }
 
 func (m *metadataManager) ensureDirectory() error {
@@ -0,0 +1,184 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestMetadataMigrations(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		want      map[string]ServerMetadata
+		wantPrefs metadataPreferences
+	}{
+		{
+			name:    "missing file",
+			content: "",
+			want:    map[string]ServerMetadata{},
+		},
+		{
+			name:      "version 1 flat map",
+			content:   `{"web": {"tags": ["prod"], "ssh_count": 3}, "@preferences": {"last_config_file": "/tmp/config"}}`,
+			want:      map[string]ServerMetadata{"web": {Tags: []string{"prod"}, SSHCount: 3}},
+			wantPrefs: metadataPreferences{LastConfigFile: "/tmp/config"},
+		},
+		{
+			name:    "version 1 with a host named version",
+			content: `{"version": {"pinned_at": "2025-01-01T00:00:00Z"}}`,
+			want:    map[string]ServerMetadata{"version": {PinnedAt: "2025-01-01T00:00:00Z"}},
+		},
+		{
+			name:      "version 2",
+			content:   `{"version": 2, "preferences": {"last_config_file": "/tmp/config"}, "servers": {"web": {"last_seen": "2025-01-01T00:00:00Z"}}}`,
+			want:      map[string]ServerMetadata{"web": {LastSeen: "2025-01-01T00:00:00Z"}},
+			wantPrefs: metadataPreferences{LastConfigFile: "/tmp/config"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "metadata.json")
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			m := newMetadataManager(path, zap.NewNop().Sugar())
+
+			metadata, prefs, hash, err := m.loadForUpdate()
+			if err != nil {
+				t.Fatalf("loadForUpdate() error = %v", err)
+			}
+			assertMetadata(t, metadata, prefs, tt.want, tt.wantPrefs)
+
+			// Saving writes the current version, which must load back unchanged.
+			if err := m.save(metadata, prefs, hash); err != nil {
+				t.Fatalf("save() error = %v", err)
+			}
+			data, _ := os.ReadFile(path)
+			var doc metadataDocument
+			if err := json.Unmarshal(data, &doc); err != nil || doc.Version != metadataVersion {
+				t.Errorf("saved metadata = %s, want version %d", data, metadataVersion)
+			}
+			metadata, prefs, err = m.load()
+			if err != nil {
+				t.Fatalf("load() after save error = %v", err)
+			}
+			assertMetadata(t, metadata, prefs, tt.want, tt.wantPrefs)
+		})
+	}
+}
+
+func assertMetadata(t *testing.T, got map[string]ServerMetadata, gotPrefs metadataPreferences, want map[string]ServerMetadata, wantPrefs metadataPreferences) {
+	t.Helper()
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("metadata = %s, want %s", gotJSON, wantJSON)
+	}
+	if gotPrefs != wantPrefs {
+		t.Errorf("preferences = %+v, want %+v", gotPrefs, wantPrefs)
+	}
+}
+
+func TestMetadataFromNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metadata.json")
+	content := `{"version": 99, "servers": {}}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := newMetadataManager(path, zap.NewNop().Sugar())
+
+	if _, err := m.loadAll(); !errors.Is(err, ErrMetadataVersion) {
+		t.Errorf("loadAll() error = %v, want ErrMetadataVersion", err)
+	}
+	if err := m.setPinned("web", true); err == nil {
+		t.Errorf("setPinned() on a newer metadata file succeeded, want error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("metadata of a newer version was overwritten:\n%s", data)
+	}
+}
+
+func TestMetadataCorruptionRecovery(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "metadata.json")
+	m := newMetadataManager(path, zap.NewNop().Sugar())
+
+	if err := m.setPinned("web", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.recordSSH("web"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"version": 2, "servers": {"web": `), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The last good copy is the content before recordSSH: pinned, never connected.
+	metadata, err := m.loadAll()
+	if err != nil {
+		t.Fatalf("loadAll() of a corrupt file error = %v", err)
+	}
+	if web := metadata["web"]; web.PinnedAt == "" || web.SSHCount != 0 {
+		t.Errorf("recovered metadata = %+v, want the last good copy", web)
+	}
+
+	if err := m.recordSSH("web"); err != nil {
+		t.Fatalf("recordSSH() after recovery error = %v", err)
+	}
+	metadata, _ = m.loadAll()
+	if web := metadata["web"]; web.PinnedAt == "" || web.SSHCount != 1 {
+		t.Errorf("metadata after saving over a corrupt file = %+v", web)
+	}
+	// The corrupt content must not have replaced the last good copy.
+	if _, _, err := m.decode(mustRead(t, path+lastGoodSuffix)); err != nil {
+		t.Errorf("last good copy is not valid metadata: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), TempSuffix) {
+			t.Errorf("temporary file %s left behind", entry.Name())
+		}
+	}
+
+	if err := os.WriteFile(path+lastGoodSuffix, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.loadAll(); err == nil {
+		t.Errorf("loadAll() with no good copy succeeded, want error")
+	}
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,178 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// scannedHost is a Host block as seen by scanHosts: its concrete aliases and, when
+// metadata is kept in the config, the tags annotated on it.
+type scannedHost struct {
+	aliases   []string
+	tags      []string
+	annotated bool
+}
+
+// ListAliases returns the aliases of every server in the main config and its includes,
+// sorted and without duplicates. Secondary aliases of a Host are included, since ssh
+// accepts them too. Unlike ListServers the files are only scanned for Host and Include
+// lines instead of being parsed, so it stays fast enough for shell completion on configs
+// with thousands of hosts.
+func (r *Repository) ListAliases() ([]string, error) {
+	hosts, err := r.scanHosts()
+	if err != nil {
+		return nil, err
+	}
+	aliases := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		aliases = append(aliases, host.aliases...)
+	}
+	return sortedUnique(aliases), nil
+}
+
+// ListTags returns the tags of every server, sorted and without duplicates. Like
+// ListAliases it scans the config files rather than parsing them.
+func (r *Repository) ListTags() ([]string, error) {
+	hosts, err := r.scanHosts()
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := r.metadataManager.loadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	tags := make([]string, 0)
+	for _, host := range hosts {
+		if r.annotationsInConfig() && host.annotated {
+			tags = append(tags, host.tags...)
+		} else if meta, ok := metadata[host.aliases[0]]; ok {
+			tags = append(tags, meta.Tags...)
+		}
+	}
+	return sortedUnique(tags), nil
+}
+
+// scanHosts returns the Host blocks with at least one concrete alias from the main config
+// and, depth-first, the files it includes. It follows the same rules as loadConfigFiles and
+// toDomainServer: a missing main config has no hosts, unreadable includes are skipped and
+// patterns with wildcards or negations are not aliases.
+func (r *Repository) scanHosts() ([]scannedHost, error) {
+	hosts := make([]scannedHost, 0)
+	visited := map[string]bool{filepath.Clean(r.configPath): true}
+	content, err := r.readFileContent(r.configPath)
+	if err != nil {
+		if r.fileSystem.IsNotExist(err) {
+			return hosts, nil
+		}
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	r.scanConfig(content, visited, &hosts)
+	return hosts, nil
+}
+
+// scanConfig appends the hosts of one config file to hosts, followed by those of the files it includes.
+func (r *Repository) scanConfig(content []byte, visited map[string]bool, hosts *[]scannedHost) {
+	var current *scannedHost
+	var includes []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if comment, ok := strings.CutPrefix(line, "#"); ok {
+			key, value, ok := strings.Cut(strings.TrimLeft(comment, " \t"), "=")
+			key, isAnnotation := strings.CutPrefix(key, annotationPrefix)
+			if current == nil || !ok || !isAnnotation {
+				continue
+			}
+			current.annotated = true
+			if key == "tags" {
+				current.tags = nil
+				for _, tag := range strings.Split(value, ",") {
+					if tag = strings.TrimSpace(tag); tag != "" {
+						current.tags = append(current.tags, tag)
+					}
+				}
+			}
+			continue
+		}
+
+		keyword, value := splitKeyword(line)
+		switch strings.ToLower(keyword) {
+		case "host":
+			current = nil
+			aliases := make([]string, 0)
+			for _, pattern := range strings.Fields(value) {
+				if !strings.ContainsAny(pattern, "!*?[]") {
+					aliases = append(aliases, pattern)
+				}
+			}
+			if len(aliases) > 0 {
+				*hosts = append(*hosts, scannedHost{aliases: aliases})
+				current = &(*hosts)[len(*hosts)-1]
+			}
+		case "match":
+			current = nil
+		case "include":
+			includes = append(includes, r.resolveInclude(strings.Fields(value))...)
+		}
+	}
+
+	for _, path := range includes {
+		if visited[path] {
+			continue
+		}
+		visited[path] = true
+		child, err := r.readFileContent(path)
+		if err != nil {
+			r.logger.Warnf("failed to read included config file %s: %v", path, err)
+			continue
+		}
+		r.scanConfig(child, visited, hosts)
+	}
+}
+
+// splitKeyword splits a config line into its keyword and value, which are separated by
+// whitespace or a single "=". A trailing comment is dropped from the value.
+func splitKeyword(line string) (string, string) {
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return line, ""
+	}
+	value := strings.TrimLeft(line[end:], " \t")
+	value = strings.TrimPrefix(value, "=")
+	if idx := strings.Index(value, "#"); idx >= 0 {
+		value = value[:idx]
+	}
+	return line[:end], strings.TrimSpace(value)
+}
+
+// sortedUnique sorts values and removes duplicates.
+func sortedUnique(values []string) []string {
+	sort.Strings(values)
+	unique := values[:0]
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,85 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/adapters/data/settings"
+)
+
+func TestListAliasesAndTags(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "config", `Include config.d/*
+Host *
+    ServerAliveInterval 30
+
+Host=web www # main site
+    # lazyssh:tags=prod, eu
+    HostName 10.0.0.1
+Host !bastion *.internal db-?
+Match host web
+    # lazyssh:tags=ignored
+    User deploy
+Host db
+    HostName 10.0.0.2
+`)
+	writeTestFile(t, dir, "config.d/team", "host cache\n    HostName 10.0.0.3\nHost web\n")
+	metadata := `{"version": 2, "servers": {"db": {"tags": ["staging"]}, "cache": {"tags": ["prod"]}, "gone": {"tags": ["old"]}}}`
+	if err := os.WriteFile(filepath.Join(dir, "metadata.json"), []byte(metadata), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r := newTestRepository(t, dir)
+
+	aliases, err := r.ListAliases()
+	if err != nil {
+		t.Fatalf("ListAliases() error = %v", err)
+	}
+	if want := []string{"cache", "db", "web", "www"}; !reflect.DeepEqual(aliases, want) {
+		t.Errorf("ListAliases() = %q, want %q", aliases, want)
+	}
+	servers, err := r.ListServers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := make([]string, 0)
+	for _, server := range servers {
+		parsed = append(parsed, server.Aliases...)
+	}
+	if parsed = sortedUnique(parsed); !reflect.DeepEqual(aliases, parsed) {
+		t.Errorf("ListAliases() = %q, but ListServers() has aliases %q", aliases, parsed)
+	}
+
+	tests := []struct {
+		store string
+		want  []string
+	}{
+		{settings.MetadataStoreFile, []string{"prod", "staging"}},
+		{settings.MetadataStoreConfig, []string{"eu", "prod", "staging"}},
+	}
+	for _, tt := range tests {
+		r.metadataStore = tt.store
+		tags, err := r.ListTags()
+		if err != nil {
+			t.Fatalf("ListTags() with %s store error = %v", tt.store, err)
+		}
+		if !reflect.DeepEqual(tags, tt.want) {
+			t.Errorf("ListTags() with %s store = %q, want %q", tt.store, tags, tt.want)
+		}
+	}
+}
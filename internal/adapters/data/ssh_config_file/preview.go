@@ -0,0 +1,52 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import "strings"
+
+// pendingWrites holds, by path, the content a previewed change would write.
+type pendingWrites map[string][]byte
+
+// Preview runs change without writing any file and returns a unified diff of the config
+// and metadata files it would modify. Writes are kept in memory, where later reads of the
+// same change see them; nothing is backed up and the undo history is left as it was.
+func (r *Repository) Preview(change func() error) (string, error) {
+	r.history.mu.Lock()
+	undo, redo := r.history.undo, r.history.redo
+	r.history.mu.Unlock()
+
+	pending := make(pendingWrites)
+	r.preview, r.metadataManager.preview = pending, pending
+	err := change()
+	paths := r.trackedPaths()
+	r.preview, r.metadataManager.preview = nil, nil
+
+	r.history.mu.Lock()
+	r.history.undo, r.history.redo = undo, redo
+	r.history.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, path := range paths {
+		content, ok := pending[path]
+		if !ok {
+			continue
+		}
+		sb.WriteString(unifiedDiff(path, path, r.readSnapshot(path).content, content))
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestPreview(t *testing.T) {
+	dir := t.TempDir()
+	content := "Host web\n    HostName 10.0.0.1\n"
+	path := writeTestFile(t, dir, "config", content)
+	r := newTestRepository(t, dir)
+
+	diff, err := r.Preview(func() error {
+		if err := r.AddServer(domain.Server{Alias: "db", Host: "10.0.0.2", Tags: []string{"prod"}}); err != nil {
+			return err
+		}
+		// The second change sees the first one.
+		return r.SetPinned("db", true)
+	})
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	for _, want := range []string{"+++ " + path, "Host db", "+    HostName 10.0.0.2", "metadata.json", `+      "tags": [`, `"pinned_at"`} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff does not contain %q:\n%s", want, diff)
+		}
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("Preview() wrote the config:\n%s", data)
+	}
+	if _, err := os.Stat(r.metadataManager.filePath); !os.IsNotExist(err) {
+		t.Errorf("Preview() wrote the metadata file")
+	}
+	if backups, _ := r.ListBackups(); len(backups) != 0 {
+		t.Errorf("Preview() made %d backups", len(backups))
+	}
+	if _, err := r.Undo(); err == nil {
+		t.Errorf("Preview() recorded the change for undo")
+	}
+
+	if _, err := r.Preview(func() error { return r.AddServer(domain.Server{Alias: "web", Host: "x"}) }); err == nil {
+		t.Errorf("Preview() of a failing change succeeded, want error")
+	}
+}
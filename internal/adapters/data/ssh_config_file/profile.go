@@ -0,0 +1,213 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/kevinburke/ssh_config"
+)
+
+// ListProfiles returns every Host block made only of wildcard patterns, together
+// with the aliases of the servers each one applies to.
+func (r *Repository) ListProfiles() ([]domain.Profile, error) {
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	aliases := make([]string, 0)
+	for _, file := range files {
+		for _, server := range r.toDomainServer(file.cfg, file.path) {
+			aliases = append(aliases, server.Aliases...)
+		}
+	}
+
+	profiles := make([]domain.Profile, 0)
+	for _, file := range files {
+		for _, host := range file.cfg.Hosts {
+			if !isProfileHost(host) {
+				continue
+			}
+			profile := domain.Profile{
+				Server: domain.Server{
+					Alias:         hostPatterns(host),
+					IdentityFiles: []string{},
+					SourceFile:    file.path,
+				},
+				Matches: make([]string, 0),
+			}
+			for _, node := range host.Nodes {
+				if kv, ok := node.(*ssh_config.KV); ok {
+					r.mapKVToServer(&profile.Server, kv)
+				}
+			}
+			for _, alias := range aliases {
+				if host.Matches(alias) {
+					profile.Matches = append(profile.Matches, alias)
+				}
+			}
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, nil
+}
+
+// AddProfile appends a new pattern Host block to the chosen config file. The change can be undone.
+func (r *Repository) AddProfile(profile domain.Profile) error {
+	return r.tracked("add profile "+profile.Alias, func() error {
+		return r.addProfile(profile)
+	})
+}
+
+func (r *Repository) addProfile(profile domain.Profile) error {
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if file, _ := r.findProfileHost(files, profile.Alias, ""); file != nil {
+		return fmt.Errorf("profile '%s' already exists", profile.Alias)
+	}
+
+	target, err := r.targetConfigFile(files, profile.SourceFile)
+	if err != nil {
+		return err
+	}
+	host := r.createHostFromServer(profile.Server)
+	host.Patterns = profilePatterns(profile.Alias)
+	target.cfg.Hosts = append(target.cfg.Hosts, host)
+
+	if err := r.saveConfig(target); err != nil {
+		r.logger.Warnf("Failed to save config while adding profile: %v", err)
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// UpdateProfile updates the patterns and settings of an existing pattern Host block. The change can be undone.
+func (r *Repository) UpdateProfile(profile domain.Profile, newProfile domain.Profile) error {
+	return r.tracked("edit profile "+profile.Alias, func() error {
+		return r.updateProfile(profile, newProfile)
+	})
+}
+
+func (r *Repository) updateProfile(profile domain.Profile, newProfile domain.Profile) error {
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	file, host := r.findProfileHost(files, profile.Alias, profile.SourceFile)
+	if host == nil {
+		return fmt.Errorf("profile '%s' not found", profile.Alias)
+	}
+
+	if profile.Alias != newProfile.Alias {
+		if existing, _ := r.findProfileHost(files, newProfile.Alias, ""); existing != nil {
+			return fmt.Errorf("profile '%s' already exists", newProfile.Alias)
+		}
+		host.Patterns = profilePatterns(newProfile.Alias)
+	}
+
+	r.updateHostNodes(host, newProfile.Server)
+
+	if err := r.saveConfig(file); err != nil {
+		r.logger.Warnf("Failed to save config while updating profile: %v", err)
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// DeleteProfile removes a pattern Host block from the config file that defines it. The change can be undone.
+func (r *Repository) DeleteProfile(profile domain.Profile) error {
+	return r.tracked("delete profile "+profile.Alias, func() error {
+		return r.deleteProfile(profile)
+	})
+}
+
+func (r *Repository) deleteProfile(profile domain.Profile) error {
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	file, host := r.findProfileHost(files, profile.Alias, profile.SourceFile)
+	if host == nil {
+		return fmt.Errorf("profile '%s' not found", profile.Alias)
+	}
+
+	for i, h := range file.cfg.Hosts {
+		if h == host {
+			file.cfg.Hosts = append(file.cfg.Hosts[:i], file.cfg.Hosts[i+1:]...)
+			break
+		}
+	}
+
+	if err := r.saveConfig(file); err != nil {
+		r.logger.Warnf("Failed to save config while deleting profile: %v", err)
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// findProfileHost finds the pattern Host block whose pattern list equals patterns.
+// When sourceFile is set only that file is searched.
+func (r *Repository) findProfileHost(files []*configFile, patterns, sourceFile string) (*configFile, *ssh_config.Host) {
+	want := strings.Join(strings.Fields(patterns), " ")
+	for _, file := range files {
+		if sourceFile != "" && filepath.Clean(file.path) != filepath.Clean(sourceFile) {
+			continue
+		}
+		for _, host := range file.cfg.Hosts {
+			if isProfileHost(host) && hostPatterns(host) == want {
+				return file, host
+			}
+		}
+	}
+	return nil, nil
+}
+
+// isProfileHost reports whether host is an explicit Host block without any concrete alias.
+func isProfileHost(host *ssh_config.Host) bool {
+	if host.Implicit || len(host.Patterns) == 0 {
+		return false
+	}
+	for _, pattern := range host.Patterns {
+		if !strings.ContainsAny(pattern.String(), "!*?[]") {
+			return false
+		}
+	}
+	return true
+}
+
+// profilePatterns splits a space-separated pattern list into Host patterns.
+func profilePatterns(patterns string) []*ssh_config.Pattern {
+	fields := strings.Fields(patterns)
+	result := make([]*ssh_config.Pattern, 0, len(fields))
+	for _, field := range fields {
+		pattern, err := ssh_config.NewPattern(field)
+		if err != nil {
+			pattern = &ssh_config.Pattern{}
+		}
+		// NewPattern drops a leading "!" from Str; keep it so negations are written back.
+		pattern.Str = field
+		result = append(result, pattern)
+	}
+	return result
+}
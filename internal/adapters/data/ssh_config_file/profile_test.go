@@ -0,0 +1,89 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	content := `# team defaults
+Host web.staging db.staging
+    HostName 10.0.0.1
+
+Host bastion-eu
+    HostName 10.0.0.2
+
+Host *.staging
+    User deploy
+    ProxyJump bastion-eu
+`
+	path := writeTestFile(t, dir, "config", content)
+	r := newTestRepository(t, dir)
+
+	profiles, err := r.ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+	if len(profiles) != 1 {
+		t.Fatalf("ListProfiles() returned %d profiles, want 1", len(profiles))
+	}
+	staging := profiles[0]
+	if staging.Alias != "*.staging" || staging.User != "deploy" || staging.ProxyJump != "bastion-eu" || staging.SourceFile != path {
+		t.Errorf("profile = %+v, want *.staging with User deploy and ProxyJump bastion-eu", staging.Server)
+	}
+	if want := []string{"web.staging", "db.staging"}; !reflect.DeepEqual(staging.Matches, want) {
+		t.Errorf("Matches = %v, want %v", staging.Matches, want)
+	}
+
+	bastions := domain.Profile{Server: domain.Server{Alias: "bastion-* !bastion-test", User: "jump"}}
+	if err := r.AddProfile(bastions); err != nil {
+		t.Fatalf("AddProfile() error = %v", err)
+	}
+	if err := r.AddProfile(bastions); err == nil {
+		t.Errorf("AddProfile() with existing patterns succeeded, want error")
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), content) || !strings.Contains(string(data), "Host bastion-* !bastion-test") {
+		t.Errorf("AddProfile() did not append the block non-destructively:\n%s", data)
+	}
+
+	updated := staging
+	updated.Alias = "*.staging *.qa"
+	updated.User = "ci"
+	if err := r.UpdateProfile(staging, updated); err != nil {
+		t.Fatalf("UpdateProfile() error = %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	// Rewriting the file must keep the negated pattern of the other profile intact.
+	if !strings.Contains(string(data), "Host *.staging *.qa\n    User ci\n    ProxyJump bastion-eu") ||
+		!strings.Contains(string(data), "Host bastion-* !bastion-test") {
+		t.Errorf("UpdateProfile() result:\n%s", data)
+	}
+
+	if err := r.DeleteProfile(domain.Profile{Server: domain.Server{Alias: "*.staging *.qa"}}); err != nil {
+		t.Fatalf("DeleteProfile() error = %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "*.qa") || !strings.Contains(string(data), "Host web.staging db.staging") {
+		t.Errorf("DeleteProfile() result:\n%s", data)
+	}
+}
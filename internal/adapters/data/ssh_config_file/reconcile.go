@@ -0,0 +1,175 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// serverFingerprint identifies a server by where it connects to: its HostName, User and Port.
+// Servers without a HostName have no fingerprint, since their alias is also their address.
+func serverFingerprint(server domain.Server) string {
+	if server.Host == "" {
+		return ""
+	}
+	port := server.Port
+	if port == 0 {
+		port = 22
+	}
+	return strings.ToLower(server.Host) + "|" + server.User + "|" + strconv.Itoa(port)
+}
+
+// ReconcileMetadata records the fingerprint of every metadata entry whose server exists and
+// returns the entries whose alias is gone from the config. An orphan is matched to a server
+// when exactly one server without metadata of its own has the fingerprint the entry recorded.
+func (r *Repository) ReconcileMetadata() ([]domain.MetadataOrphan, error) {
+	var orphans []domain.MetadataOrphan
+	err := r.ownWrite(func() error {
+		files, err := r.loadConfigFiles()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		servers := make(map[string]domain.Server)
+		for _, file := range files {
+			for _, server := range r.toDomainServer(file.cfg, file.path) {
+				if _, ok := servers[server.Alias]; !ok {
+					servers[server.Alias] = server
+				}
+			}
+		}
+
+		metadata, prefs, hash, err := r.metadataManager.loadForUpdate()
+		if err != nil {
+			return fmt.Errorf("load metadata: %w", err)
+		}
+		changed := false
+		candidates := make(map[string][]string)
+		for alias, server := range servers {
+			fingerprint := serverFingerprint(server)
+			meta, ok := metadata[alias]
+			switch {
+			case !ok:
+				if fingerprint != "" {
+					candidates[fingerprint] = append(candidates[fingerprint], alias)
+				}
+			case meta.Fingerprint != fingerprint:
+				meta.Fingerprint = fingerprint
+				metadata[alias] = meta
+				changed = true
+			}
+		}
+		if changed {
+			if err := r.metadataManager.save(metadata, prefs, hash); err != nil {
+				return err
+			}
+		}
+
+		claims := make(map[string]int)
+		for alias, meta := range metadata {
+			if _, ok := servers[alias]; !ok && meta.Fingerprint != "" {
+				claims[meta.Fingerprint]++
+			}
+		}
+		orphans = make([]domain.MetadataOrphan, 0)
+		for alias, meta := range metadata {
+			if _, ok := servers[alias]; ok {
+				continue
+			}
+			orphan := domain.MetadataOrphan{
+				Alias:    alias,
+				Tags:     meta.Tags,
+				SSHCount: meta.SSHCount,
+				Pinned:   meta.PinnedAt != "",
+			}
+			if matches := candidates[meta.Fingerprint]; meta.Fingerprint != "" && len(matches) == 1 && claims[meta.Fingerprint] == 1 {
+				orphan.MatchedAlias = matches[0]
+			}
+			orphans = append(orphans, orphan)
+		}
+		sort.Slice(orphans, func(i, j int) bool { return orphans[i].Alias < orphans[j].Alias })
+		return nil
+	})
+	return orphans, err
+}
+
+// MigrateMetadata moves the metadata of oldAlias to newAlias. The change can be undone.
+func (r *Repository) MigrateMetadata(oldAlias, newAlias string) error {
+	return r.tracked("move metadata of "+oldAlias+" to "+newAlias, func() error {
+		return r.metadataManager.moveServer(oldAlias, newAlias)
+	})
+}
+
+// PurgeMetadata removes the metadata of alias. The change can be undone.
+func (r *Repository) PurgeMetadata(alias string) error {
+	return r.tracked("purge metadata of "+alias, func() error {
+		return r.metadataManager.deleteServer(alias)
+	})
+}
+
+// moveServer moves the entry of oldAlias to newAlias. If newAlias has an entry of its own
+// the two are combined: fields it lacks are taken from oldAlias and connections are added up.
+func (m *metadataManager) moveServer(oldAlias, newAlias string) error {
+	metadata, prefs, hash, err := m.loadForUpdate()
+	if err != nil {
+		m.logger.Errorw("failed to load metadata in moveServer", "path", m.filePath, "alias", newAlias, "old_alias", oldAlias, "error", err)
+		return fmt.Errorf("load metadata: %w", err)
+	}
+
+	old, ok := metadata[oldAlias]
+	if !ok {
+		return fmt.Errorf("no metadata for '%s'", oldAlias)
+	}
+	merged, ok := metadata[newAlias]
+	if !ok {
+		merged = old
+	} else {
+		if len(merged.Tags) == 0 {
+			merged.Tags = old.Tags
+		}
+		if merged.Notes == "" {
+			merged.Notes = old.Notes
+		}
+		if len(merged.Fields) == 0 {
+			merged.Fields = old.Fields
+		}
+		if merged.PinnedAt == "" {
+			merged.PinnedAt = old.PinnedAt
+		}
+		if laterTimestamp(old.LastSeen, merged.LastSeen) {
+			merged.LastSeen = old.LastSeen
+		}
+		merged.SSHCount += old.SSHCount
+	}
+
+	delete(metadata, oldAlias)
+	metadata[newAlias] = merged
+	return m.save(metadata, prefs, hash)
+}
+
+// laterTimestamp reports whether the RFC 3339 timestamp a is after b; unparsable timestamps count as unset.
+func laterTimestamp(a, b string) bool {
+	ta, err := time.Parse(time.RFC3339, a)
+	if err != nil {
+		return false
+	}
+	tb, err := time.Parse(time.RFC3339, b)
+	return err != nil || ta.After(tb)
+}
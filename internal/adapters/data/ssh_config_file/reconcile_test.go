@@ -0,0 +1,88 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestReconcileMetadata(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "config", "Host web\n    HostName 10.0.0.1\n    User deploy\n\nHost db\n    HostName 10.0.0.2\n\nHost old\n    HostName 10.0.0.3\n")
+	r := newTestRepository(t, dir)
+
+	for _, alias := range []string{"web", "db", "old"} {
+		if err := r.RecordSSH(alias); err != nil {
+			t.Fatalf("RecordSSH(%s) error = %v", alias, err)
+		}
+	}
+	if err := r.SetPinned("web", true); err != nil {
+		t.Fatalf("SetPinned() error = %v", err)
+	}
+	if orphans, err := r.ReconcileMetadata(); err != nil || len(orphans) != 0 {
+		t.Fatalf("ReconcileMetadata() = %v, %v, want no orphans", orphans, err)
+	}
+
+	// Rename web and db outside lazyssh, and drop old.
+	renamed := "Host www\n    HostName 10.0.0.1\n    User deploy\n\nHost database\n    HostName 10.0.0.2\n    Port 2222\n"
+	if err := os.WriteFile(path, []byte(renamed), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	orphans, err := r.ReconcileMetadata()
+	if err != nil {
+		t.Fatalf("ReconcileMetadata() error = %v", err)
+	}
+	want := []domain.MetadataOrphan{
+		{Alias: "db", SSHCount: 1},
+		{Alias: "old", SSHCount: 1},
+		{Alias: "web", MatchedAlias: "www", SSHCount: 1, Pinned: true},
+	}
+	if len(orphans) != len(want) {
+		t.Fatalf("ReconcileMetadata() = %+v, want %+v", orphans, want)
+	}
+	for i := range want {
+		if got := orphans[i]; got.Alias != want[i].Alias || got.MatchedAlias != want[i].MatchedAlias ||
+			got.SSHCount != want[i].SSHCount || got.Pinned != want[i].Pinned {
+			t.Errorf("orphan %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	if err := r.MigrateMetadata("web", "www"); err != nil {
+		t.Fatalf("MigrateMetadata() error = %v", err)
+	}
+	if err := r.PurgeMetadata("old"); err != nil {
+		t.Fatalf("PurgeMetadata() error = %v", err)
+	}
+	if www := findServer(t, r, "www"); www.PinnedAt.IsZero() || www.SSHCount != 1 {
+		t.Errorf("www after migrate = pinned %v, %d connections, want pinned with 1", www.PinnedAt, www.SSHCount)
+	}
+	metadata, err := r.metadataManager.loadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := metadata["web"]; ok {
+		t.Errorf("metadata of web still present after migrate")
+	}
+	if _, ok := metadata["old"]; ok {
+		t.Errorf("metadata of old still present after purge")
+	}
+
+	if desc, err := r.Undo(); err != nil || desc != "purge metadata of old" {
+		t.Errorf("Undo() = %q, %v, want purge metadata of old", desc, err)
+	}
+}
@@ -16,7 +16,9 @@ package ssh_config_file
 
 import (
 	"fmt"
+	"reflect"
 
+	"github.com/Adembc/lazyssh/internal/adapters/data/settings"
 	"github.com/Adembc/lazyssh/internal/core/domain"
 	"github.com/Adembc/lazyssh/internal/core/ports"
 	"github.com/kevinburke/ssh_config"
@@ -28,86 +30,112 @@ type Repository struct {
 	configPath      string
 	fileSystem      FileSystem
 	metadataManager *metadataManager
+	backups         settings.Backup
+	metadataStore   string // settings.MetadataStoreFile or settings.MetadataStoreConfig
+	history         *history
+	watch           watchState
+	operation       string        // the change being made, recorded with the backups it produces
+	preview         pendingWrites // set while a change is previewed, see Preview
 	logger          *zap.SugaredLogger
 }
 
 // NewRepository creates a new SSH config repository.
-func NewRepository(logger *zap.SugaredLogger, configPath, metaDataPath string) ports.ServerRepository {
-	return &Repository{
-		logger:          logger,
-		configPath:      configPath,
-		fileSystem:      DefaultFileSystem{},
-		metadataManager: newMetadataManager(metaDataPath, logger),
-	}
+func NewRepository(logger *zap.SugaredLogger, configPath, metaDataPath string, appSettings settings.Settings) ports.ServerRepository {
+	return NewRepositoryWithFS(logger, configPath, metaDataPath, appSettings, DefaultFileSystem{})
 }
 
 // NewRepositoryWithFS creates a new SSH config repository with a custom filesystem.
-func NewRepositoryWithFS(logger *zap.SugaredLogger, configPath string, metaDataPath string, fs FileSystem) ports.ServerRepository {
+func NewRepositoryWithFS(logger *zap.SugaredLogger, configPath string, metaDataPath string, appSettings settings.Settings, fs FileSystem) ports.ServerRepository {
+	metadataManager := newMetadataManager(metaDataPath, logger)
+	metadataManager.localOnly = appSettings.Metadata.Store == settings.MetadataStoreConfig
 	return &Repository{
 		logger:          logger,
 		configPath:      configPath,
 		fileSystem:      fs,
-		metadataManager: newMetadataManager(metaDataPath, logger),
+		metadataManager: metadataManager,
+		backups:         appSettings.Backup,
+		metadataStore:   appSettings.Metadata.Store,
+		history:         &history{},
 	}
 }
 
-// ListServers returns all servers matching the query pattern.
-// Empty query returns all servers.
-func (r *Repository) ListServers(query string) ([]domain.Server, error) {
-	cfg, err := r.loadConfig()
+// ListServers returns all servers, collected from the main config and every file it includes.
+// Their effective configuration is left empty; it is resolved on demand with ResolveEffective.
+func (r *Repository) ListServers() ([]domain.Server, error) {
+	files, err := r.loadConfigFiles()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	servers := r.toDomainServer(cfg)
+	servers := make([]domain.Server, 0)
+	for _, file := range files {
+		servers = append(servers, r.toDomainServer(file.cfg, file.path)...)
+	}
 	metadata, err := r.metadataManager.loadAll()
 	if err != nil {
-		r.logger.Warnf("Failed to load metadata: %v", err)
+		r.logger.Errorf("Failed to load metadata, tags and pins are not shown: %v", err)
 		metadata = make(map[string]ServerMetadata)
 	}
-	servers = r.mergeMetadata(servers, metadata)
-	if query == "" {
-		return servers, nil
-	}
-
-	return r.filterServers(servers, query), nil
+	return r.mergeMetadata(servers, metadata), nil
 }
 
-// AddServer adds a new server to the SSH config.
+// AddServer adds a new server to the SSH config. The change can be undone.
 func (r *Repository) AddServer(server domain.Server) error {
-	cfg, err := r.loadConfig()
+	return r.tracked("add server "+server.Alias, func() error {
+		return r.addServer(server)
+	})
+}
+
+func (r *Repository) addServer(server domain.Server) error {
+	files, err := r.loadConfigFiles()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if r.serverExists(cfg, server.Alias) {
+	if r.serverExists(files, server.Alias) {
 		return fmt.Errorf("server with alias '%s' already exists", server.Alias)
 	}
 
+	target, err := r.targetConfigFile(files, server.SourceFile)
+	if err != nil {
+		return err
+	}
 	host := r.createHostFromServer(server)
-	cfg.Hosts = append(cfg.Hosts, host)
+	if r.annotationsInConfig() {
+		writeAnnotations(host, server)
+	}
+	target.cfg.Hosts = append(target.cfg.Hosts, host)
 
-	if err := r.saveConfig(cfg); err != nil {
+	if err := r.saveConfig(target); err != nil {
 		r.logger.Warnf("Failed to save config while adding new server: %v", err)
 		return fmt.Errorf("failed to save config: %w", err)
 	}
+	if err := r.metadataManager.setLastConfigFile(target.path); err != nil {
+		r.logger.Warnf("Failed to remember target config file: %v", err)
+	}
 	return r.metadataManager.updateServer(server, server.Alias)
 }
 
-// UpdateServer updates an existing server in the SSH config.
+// UpdateServer updates an existing server in the config file that defines it. The change can be undone.
 func (r *Repository) UpdateServer(server domain.Server, newServer domain.Server) error {
-	cfg, err := r.loadConfig()
+	return r.tracked(describeUpdate(server, newServer), func() error {
+		return r.updateServer(server, newServer)
+	})
+}
+
+func (r *Repository) updateServer(server domain.Server, newServer domain.Server) error {
+	files, err := r.loadConfigFiles()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	host := r.findHostByAlias(cfg, server.Alias)
+	file, host := r.findConfigFileByAlias(files, server.Alias)
 	if host == nil {
 		return fmt.Errorf("server with alias '%s' not found", server.Alias)
 	}
 
 	if server.Alias != newServer.Alias {
-		if r.serverExists(cfg, newServer.Alias) {
+		if r.serverExists(files, newServer.Alias) {
 			return fmt.Errorf("server with alias '%s' already exists", newServer.Alias)
 		}
 
@@ -125,8 +153,11 @@ func (r *Repository) UpdateServer(server domain.Server, newServer domain.Server)
 	}
 
 	r.updateHostNodes(host, newServer)
+	if r.annotationsInConfig() {
+		writeAnnotations(host, newServer)
+	}
 
-	if err := r.saveConfig(cfg); err != nil {
+	if err := r.saveConfig(file); err != nil {
 		r.logger.Warnf("Failed to save config while updating server: %v", err)
 		return fmt.Errorf("failed to save config: %w", err)
 	}
@@ -134,33 +165,98 @@ func (r *Repository) UpdateServer(server domain.Server, newServer domain.Server)
 	return r.metadataManager.updateServer(newServer, server.Alias)
 }
 
-// DeleteServer removes a server from the SSH config.
+// DeleteServer removes a server from the config file that defines it. The change can be undone.
 func (r *Repository) DeleteServer(server domain.Server) error {
-	cfg, err := r.loadConfig()
+	return r.tracked("delete server "+server.Alias, func() error {
+		return r.deleteServer(server)
+	})
+}
+
+func (r *Repository) deleteServer(server domain.Server) error {
+	files, err := r.loadConfigFiles()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	initialCount := len(cfg.Hosts)
-	cfg.Hosts = r.removeHostByAlias(cfg.Hosts, server.Alias)
-
-	if len(cfg.Hosts) == initialCount {
+	file, _ := r.findConfigFileByAlias(files, server.Alias)
+	if file == nil {
 		return fmt.Errorf("server with alias '%s' not found", server.Alias)
 	}
 
-	if err := r.saveConfig(cfg); err != nil {
+	file.cfg.Hosts = r.removeHostByAlias(file.cfg.Hosts, server.Alias)
+
+	if err := r.saveConfig(file); err != nil {
 		r.logger.Warnf("Failed to save config while deleting server: %v", err)
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 	return r.metadataManager.deleteServer(server.Alias)
 }
 
-// SetPinned sets or unsets the pinned status of a server.
+// ListConfigFiles returns the writable config files (the main config and its includes) new servers can be added to.
+func (r *Repository) ListConfigFiles() ([]string, error) {
+	files, err := r.loadConfigFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		if r.isWritable(file.path) {
+			paths = append(paths, file.path)
+		}
+	}
+	return paths, nil
+}
+
+// LastConfigFile returns the config file the last server was added to, or the main config if none was recorded.
+func (r *Repository) LastConfigFile() (string, error) {
+	path, err := r.metadataManager.lastConfigFile()
+	if err != nil {
+		return r.configPath, err
+	}
+	if path == "" {
+		return r.configPath, nil
+	}
+	return path, nil
+}
+
+// SetPinned sets or unsets the pinned status of a server. The change can be undone.
 func (r *Repository) SetPinned(alias string, pinned bool) error {
-	return r.metadataManager.setPinned(alias, pinned)
+	description := "unpin " + alias
+	if pinned {
+		description = "pin " + alias
+	}
+	return r.tracked(description, func() error {
+		if r.annotationsInConfig() {
+			return r.setPinnedAnnotations([]string{alias}, pinned)
+		}
+		return r.metadataManager.setPinned(alias, pinned)
+	})
 }
 
 // RecordSSH increments the SSH access count and updates the last seen timestamp for a server.
 func (r *Repository) RecordSSH(alias string) error {
-	return r.metadataManager.recordSSH(alias)
+	return r.ownWrite(func() error {
+		return r.metadataManager.recordSSH(alias)
+	})
+}
+
+// describeUpdate names an update for the undo history, calling out tag-only and notes-only edits.
+func describeUpdate(server, newServer domain.Server) string {
+	if server.Alias != newServer.Alias {
+		return fmt.Sprintf("rename server %s to %s", server.Alias, newServer.Alias)
+	}
+	tagsChanged := !reflect.DeepEqual(server.Tags, newServer.Tags)
+	notesChanged := server.Notes != newServer.Notes || !reflect.DeepEqual(server.Fields, newServer.Fields)
+	server.Tags, server.Notes, server.Fields = nil, "", nil
+	newServer.Tags, newServer.Notes, newServer.Fields = nil, "", nil
+	if reflect.DeepEqual(server, newServer) {
+		switch {
+		case tagsChanged && !notesChanged:
+			return "edit tags of " + server.Alias
+		case notesChanged && !tagsChanged:
+			return "edit notes of " + server.Alias
+		}
+	}
+	return "edit server " + server.Alias
 }
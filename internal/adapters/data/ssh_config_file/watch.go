@@ -0,0 +1,142 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WatchInterval is how often the config files and the metadata file are checked for outside changes.
+const WatchInterval = 2 * time.Second
+
+// watchState holds the content hashes of the watched files as last seen by lazyssh.
+type watchState struct {
+	mu    sync.Mutex
+	known map[string]string // nil while nobody is watching
+}
+
+// Watch polls the main config, every file it includes and the metadata file, and sends
+// the paths of files changed outside lazyssh. Changes lazyssh makes itself are not
+// reported. The returned channel is closed once stop is closed.
+func (r *Repository) Watch(stop <-chan struct{}) <-chan []string {
+	r.watch.mu.Lock()
+	r.watch.known = r.fileHashes()
+	r.watch.mu.Unlock()
+
+	changes := make(chan []string)
+	go func() {
+		defer close(changes)
+		ticker := time.NewTicker(WatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			changed := r.pollChanges()
+			if len(changed) == 0 {
+				continue
+			}
+			select {
+			case changes <- changed:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return changes
+}
+
+// ConfigVersion returns a fingerprint of the config files and the metadata file.
+// It changes whenever any of them changes, whoever changed it.
+func (r *Repository) ConfigVersion() string {
+	hashes := r.fileHashes()
+	paths := make([]string, 0, len(hashes))
+	for path := range hashes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		h.Write([]byte(path + "\x00" + hashes[path] + "\x00"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ownWrite runs write while holding the advisory locks of every file it may change and
+// with the watcher paused, then records the result as seen, so that lazyssh's own
+// changes are not reported as outside changes.
+func (r *Repository) ownWrite(write func() error) error {
+	r.watch.mu.Lock()
+	defer r.watch.mu.Unlock()
+
+	unlock, err := r.lockFiles(r.trackedPaths())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = write()
+	if r.watch.known != nil {
+		r.watch.known = r.fileHashes()
+	}
+	return err
+}
+
+// pollChanges returns the watched files whose content changed since they were last seen, sorted.
+func (r *Repository) pollChanges() []string {
+	r.watch.mu.Lock()
+	defer r.watch.mu.Unlock()
+
+	current := r.fileHashes()
+	changed := make([]string, 0)
+	for path, hash := range current {
+		if known, ok := r.watch.known[path]; !ok || known != hash {
+			changed = append(changed, path)
+		}
+	}
+	for path := range r.watch.known {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	r.watch.known = current
+	return changed
+}
+
+// fileHashes hashes the content of every watched file; missing files hash to "".
+func (r *Repository) fileHashes() map[string]string {
+	hashes := make(map[string]string)
+	for _, snap := range r.snapshot(r.trackedPaths()) {
+		hashes[snap.path] = contentHash(snap.content)
+	}
+	return hashes
+}
+
+// contentHash hashes file content; nil content, i.e. a missing file, hashes to "".
+func contentHash(content []byte) string {
+	if content == nil {
+		return ""
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
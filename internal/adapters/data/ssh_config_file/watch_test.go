@@ -0,0 +1,69 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestWatchReportsOnlyOutsideChanges(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := writeTestFile(t, dir, "config", "Include config.d/*\n\nHost web\n    HostName 10.0.0.1\n")
+	teamPath := writeTestFile(t, dir, "config.d/team", "Host db\n    HostName 10.0.0.2\n")
+	r := newTestRepository(t, dir)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	r.Watch(stop)
+	version := r.ConfigVersion()
+
+	if changed := r.pollChanges(); len(changed) != 0 {
+		t.Errorf("pollChanges() without changes = %v", changed)
+	}
+
+	if err := r.AddServer(domain.Server{Alias: "app", Host: "10.0.0.3"}); err != nil {
+		t.Fatalf("AddServer() error = %v", err)
+	}
+	if err := r.RecordSSH("app"); err != nil {
+		t.Fatalf("RecordSSH() error = %v", err)
+	}
+	if changed := r.pollChanges(); len(changed) != 0 {
+		t.Errorf("pollChanges() after lazyssh's own changes = %v, want none", changed)
+	}
+	if r.ConfigVersion() == version {
+		t.Errorf("ConfigVersion() did not change after a save")
+	}
+
+	if err := os.WriteFile(teamPath, []byte("Host db\n    HostName 10.0.0.9\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, dir, "config.d/extra", "Host new\n")
+	want := []string{filepath.Join(dir, "config.d/extra"), teamPath}
+	if changed := r.pollChanges(); !reflect.DeepEqual(changed, want) {
+		t.Errorf("pollChanges() after outside edits = %v, want %v", changed, want)
+	}
+
+	if err := os.Remove(mainPath); err != nil {
+		t.Fatal(err)
+	}
+	if changed := r.pollChanges(); len(changed) == 0 || changed[0] != mainPath {
+		t.Errorf("pollChanges() after deleting the config = %v, want %s first", changed, mainPath)
+	}
+}
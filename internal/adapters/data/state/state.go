@@ -0,0 +1,65 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package state keeps the UI state lazyssh restores on start, ~/.lazyssh/state.json.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/fsutil"
+)
+
+// Store reads and writes the UI state file.
+type Store struct {
+	path string
+}
+
+// NewStore returns a store for the state file at path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// LoadState returns the saved state; a missing file is the zero state.
+func (s *Store) LoadState() (domain.UIState, error) {
+	var state domain.UIState
+	// #nosec G304 -- the state path is fixed by lazyssh, not user-supplied
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("read state '%s': %w", s.path, err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return domain.UIState{}, fmt.Errorf("parse state '%s': %w", s.path, err)
+	}
+	return state, nil
+}
+
+// SaveState replaces the saved state, atomically.
+func (s *Store) SaveState(state domain.UIState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode state: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+	return nil
+}
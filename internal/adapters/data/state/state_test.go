@@ -0,0 +1,53 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lazyssh", "state.json")
+	store := NewStore(path)
+
+	if got, err := store.LoadState(); err != nil || got != (domain.UIState{}) {
+		t.Fatalf("LoadState() without a file = %+v, %v, want the zero state", got, err)
+	}
+
+	want := domain.UIState{
+		SortMode:      "frecency:desc",
+		Query:         "tag:prod",
+		SelectedAlias: "web",
+		HelpMode:      "compact",
+		Layout:        "tree:domain",
+	}
+	if err := store.SaveState(want); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	if got, err := store.LoadState(); err != nil || got != want {
+		t.Errorf("LoadState() = %+v, %v, want %+v", got, err, want)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.LoadState(); err == nil {
+		t.Errorf("LoadState() of a corrupt file succeeded, want error")
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+type BackupList struct {
+	*tview.List
+	backups           []domain.Backup
+	onSelectionChange func(domain.Backup)
+}
+
+func NewBackupList() *BackupList {
+	list := &BackupList{
+		List: tview.NewList(),
+	}
+	list.build()
+	return list
+}
+
+func (bl *BackupList) build() {
+	bl.List.ShowSecondaryText(false)
+	bl.List.SetBorder(true).
+		SetTitle(" Backups ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	bl.List.
+		SetSelectedBackgroundColor(tcell.Color24).
+		SetSelectedTextColor(tcell.Color255).
+		SetHighlightFullLine(true)
+
+	bl.List.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		if index >= 0 && index < len(bl.backups) && bl.onSelectionChange != nil {
+			bl.onSelectionChange(bl.backups[index])
+		}
+	})
+}
+
+func (bl *BackupList) UpdateBackups(backups []domain.Backup) {
+	bl.backups = backups
+	bl.List.Clear()
+
+	for i := range backups {
+		bl.List.AddItem(formatBackupLine(backups[i]), "", 0, nil)
+	}
+
+	if bl.List.GetItemCount() > 0 {
+		bl.List.SetCurrentItem(0)
+		if bl.onSelectionChange != nil {
+			bl.onSelectionChange(bl.backups[0])
+		}
+	}
+}
+
+func (bl *BackupList) GetSelectedBackup() (domain.Backup, bool) {
+	idx := bl.List.GetCurrentItem()
+	if idx >= 0 && idx < len(bl.backups) {
+		return bl.backups[idx], true
+	}
+	return domain.Backup{}, false
+}
+
+func (bl *BackupList) OnSelectionChange(fn func(backup domain.Backup)) *BackupList {
+	bl.onSelectionChange = fn
+	return bl
+}
+
+// backupOperation describes the change a backup was taken before.
+func backupOperation(b domain.Backup) string {
+	switch {
+	case b.Original:
+		return "original, before lazyssh's first change"
+	case b.Operation == "":
+		return "unknown change"
+	default:
+		return b.Operation
+	}
+}
+
+// formatSize renders a file size in bytes with a binary unit.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func formatBackupLine(b domain.Backup) string {
+	icon := cellPad("💾", 2)
+	if b.Original {
+		icon = cellPad("📦", 2)
+	}
+	return fmt.Sprintf("%s [white::b]%s[-] %s [#888888]%s • %s[-]",
+		icon, b.CreatedAt.Format("2006-01-02 15:04:05"), tview.Escape(filepath.Base(b.ConfigFile)),
+		formatSize(b.Size), tview.Escape(backupOperation(b)))
+}
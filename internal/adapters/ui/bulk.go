@@ -0,0 +1,66 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// bulkField is a setting that can be set across all marked servers at once.
+type bulkField struct {
+	name string
+	set  func(server *domain.Server, value string) error
+}
+
+// bulkFields are the settings offered by the "set field" action. An empty value removes the
+// setting from each server.
+var bulkFields = []bulkField{
+	{"User", func(s *domain.Server, v string) error { s.User = v; return nil }},
+	{"ProxyJump", func(s *domain.Server, v string) error { s.ProxyJump = v; return nil }},
+	{"Port", func(s *domain.Server, v string) error {
+		if v == "" {
+			s.Port = 0
+			return nil
+		}
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("port must be a number: %q", v)
+		}
+		s.Port = port
+		return nil
+	}},
+	{"IdentityFile", func(s *domain.Server, v string) error {
+		s.IdentityFiles = nil
+		if v != "" {
+			s.IdentityFiles = []string{v}
+		}
+		return nil
+	}},
+}
+
+// parseTagList splits a comma-separated list of tags, dropping empty entries.
+func parseTagList(text string) []string {
+	tags := make([]string, 0)
+	for _, part := range strings.Split(text, ",") {
+		if tag := strings.TrimSpace(part); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -138,6 +138,8 @@ func GetFieldPlaceholder(fieldName string) string {
 	// Required fields
 	case "Alias", "Host":
 		return "required"
+	case "Patterns":
+		return "required, e.g. *.staging bastion-*"
 
 	// Fields that show default value in placeholder
 	case "Port":
@@ -182,6 +184,10 @@ func GetFieldPlaceholder(fieldName string) string {
 		return "e.g., ~/.ssh/id_rsa, ~/.ssh/id_ed25519"
 	case "Tags":
 		return "comma-separated tags"
+	case "Notes":
+		return "Markdown, e.g. runbook steps and links"
+	case "Fields":
+		return "one 'key: value' per line, e.g. owner: alice"
 	case "ProxyJump": //nolint:goconst // Field name used in switch case
 		return "e.g., bastion.example.com"
 	case "ProxyCommand":
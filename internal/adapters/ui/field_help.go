@@ -35,6 +35,41 @@ const (
 	HelpModeFull                           // Detailed help with all info
 )
 
+func (m HelpDisplayMode) String() string {
+	switch m {
+	case HelpModeOff:
+		return "off"
+	case HelpModeCompact:
+		return "compact"
+	case HelpModeFull:
+		return "full"
+	default:
+		return "normal"
+	}
+}
+
+// Next cycles the help panel from detailed to compact to hidden and back.
+func (m HelpDisplayMode) Next() HelpDisplayMode {
+	switch m {
+	case HelpModeNormal, HelpModeFull:
+		return HelpModeCompact
+	case HelpModeCompact:
+		return HelpModeOff
+	default:
+		return HelpModeNormal
+	}
+}
+
+// ParseHelpDisplayMode decodes a mode written by String.
+func ParseHelpDisplayMode(s string) (HelpDisplayMode, bool) {
+	for _, m := range []HelpDisplayMode{HelpModeOff, HelpModeCompact, HelpModeNormal, HelpModeFull} {
+		if m.String() == s {
+			return m, true
+		}
+	}
+	return HelpModeNormal, false
+}
+
 // GetFieldHelp returns help information for a specific field
 func GetFieldHelp(fieldName string) *FieldHelp {
 	if help, exists := fieldHelpData[fieldName]; exists {
@@ -103,6 +138,14 @@ var fieldHelpData = map[string]FieldHelp{
 		Default:     "(required)",
 		Category:    "Basic",
 	},
+	"Patterns": {
+		Field:       "Patterns",
+		Description: "Host patterns of the profile. Its settings apply to every server whose alias matches one of the patterns, unless the server sets them itself.",
+		Syntax:      "pattern [pattern ...] (* and ? wildcards, ! negates)",
+		Examples:    []string{"*.staging", "bastion-*", "*.prod !db.prod"},
+		Default:     "(required)",
+		Category:    "Basic",
+	},
 	"Host": {
 		Field:       "Host",
 		Description: "The real hostname or IP address to connect to. Can be a domain name or IP address.",
@@ -405,12 +448,36 @@ var fieldHelpData = map[string]FieldHelp{
 	// Missing fields - Tags
 	"Tags": {
 		Field:       "Tags",
-		Description: "Custom tags for organizing and filtering servers. Comma-separated list.",
+		Description: "Custom tags for organizing and filtering servers. Comma-separated list; tags cannot contain spaces.",
 		Syntax:      "tag1[,tag2,...]  ",
 		Examples:    []string{"production", "development,staging", "web,frontend"},
 		Default:     "none",
 		Category:    "Basic",
 	},
+	"Notes": {
+		Field:       "Notes",
+		Description: "Free-form notes about the server, such as runbook steps or links. Written in Markdown and shown rendered in the details panel. Stored in lazyssh metadata, not in the SSH config.",
+		Syntax:      "Markdown",
+		Examples:    []string{"## Restart\n- `sudo systemctl restart nginx`", "[Runbook](https://wiki.example.com/web)"},
+		Default:     "none",
+		Category:    "Notes",
+	},
+	"Fields": {
+		Field:       "Fields",
+		Description: "Custom key/value fields such as owner, environment, ticket or rack. Names cannot contain = or :. Shown in the details panel and matched by search. Stored in lazyssh metadata, not in the SSH config.",
+		Syntax:      "key: value (one per line)",
+		Examples:    []string{"owner: alice", "ticket: https://jira.example.com/OPS-42"},
+		Default:     "none",
+		Category:    "Notes",
+	},
+	"TargetFile": {
+		Field:       "Target file",
+		Description: "The SSH config file the new Host block is appended to. Lists your main config and every writable file it includes. The last choice is remembered.",
+		Syntax:      "path",
+		Examples:    []string{"~/.ssh/config", "~/.ssh/config.d/team"},
+		Default:     "last used file (~/.ssh/config initially)",
+		Category:    "Basic",
+	},
 
 	// Connection - IP and Address fields
 	"IPQoS": {
@@ -0,0 +1,181 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// maxGroupTreeHeight caps the rows the groups sidebar takes from the server list.
+const maxGroupTreeHeight = 12
+
+// smartGroups are the built-in groups listed below the saved searches.
+var smartGroups = []domain.SavedSearch{
+	{Name: "Pinned", Query: "pinned:yes"},
+	{Name: "Recently used", Query: "seen:7d"},
+	{Name: "Unreachable", Query: "status:down"},
+}
+
+// groupRef is the reference of a group node in the tree.
+type groupRef struct {
+	search domain.SavedSearch
+	saved  bool // a saved search the user can delete, rather than a smart group
+}
+
+// GroupTree is the collapsible sidebar above the server list with saved searches and smart groups.
+type GroupTree struct {
+	*tview.TreeView
+	saved    *tview.TreeNode
+	smart    *tview.TreeNode
+	onSelect func(domain.SavedSearch)
+	onResize func()
+}
+
+func NewGroupTree() *GroupTree {
+	tree := &GroupTree{
+		TreeView: tview.NewTreeView(),
+		saved:    tview.NewTreeNode("").SetExpanded(true),
+		smart:    tview.NewTreeNode("").SetExpanded(true),
+	}
+	tree.build()
+	return tree
+}
+
+func (gt *GroupTree) build() {
+	root := tview.NewTreeNode("").AddChild(gt.saved).AddChild(gt.smart)
+	gt.TreeView.SetRoot(root).
+		SetTopLevel(1).
+		SetCurrentNode(gt.saved)
+	gt.TreeView.SetBorder(true).
+		SetTitle(" Groups ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	gt.TreeView.SetSelectedFunc(func(node *tview.TreeNode) {
+		if ref, ok := node.GetReference().(groupRef); ok {
+			if gt.onSelect != nil {
+				gt.onSelect(ref.search)
+			}
+			return
+		}
+		node.SetExpanded(!node.IsExpanded())
+		gt.labelCategories()
+		if gt.onResize != nil {
+			gt.onResize()
+		}
+	})
+	gt.UpdateGroups(nil, nil)
+}
+
+// UpdateGroups lists the saved searches followed by the smart groups. counts holds the number
+// of matching servers of each, in the same order; missing counts are left out.
+func (gt *GroupTree) UpdateGroups(saved []domain.SavedSearch, counts []int) {
+	var current string
+	if ref, ok := gt.TreeView.GetCurrentNode().GetReference().(groupRef); ok {
+		current = ref.search.Name
+	}
+
+	gt.saved.ClearChildren()
+	gt.smart.ClearChildren()
+	for i, search := range saved {
+		gt.saved.AddChild(newGroupNode(groupRef{search: search, saved: true}, countAt(counts, i)))
+	}
+	for i, search := range smartGroups {
+		gt.smart.AddChild(newGroupNode(groupRef{search: search}, countAt(counts, len(saved)+i)))
+	}
+	gt.labelCategories()
+
+	for _, category := range []*tview.TreeNode{gt.saved, gt.smart} {
+		for _, node := range category.GetChildren() {
+			if node.GetReference().(groupRef).search.Name == current && category.IsExpanded() {
+				gt.TreeView.SetCurrentNode(node)
+				return
+			}
+		}
+	}
+	// The selected group is gone or hidden: select the first category instead.
+	if _, ok := gt.TreeView.GetCurrentNode().GetReference().(groupRef); ok {
+		gt.TreeView.SetCurrentNode(gt.saved)
+	}
+}
+
+// GetSelectedGroup returns the selected group and whether it is a saved search.
+func (gt *GroupTree) GetSelectedGroup() (domain.SavedSearch, bool, bool) {
+	node := gt.TreeView.GetCurrentNode()
+	if node == nil {
+		return domain.SavedSearch{}, false, false
+	}
+	ref, ok := node.GetReference().(groupRef)
+	return ref.search, ref.saved, ok
+}
+
+// Height is the number of rows the tree needs to show every visible node, up to maxGroupTreeHeight.
+func (gt *GroupTree) Height() int {
+	rows := 2 // border
+	for _, category := range []*tview.TreeNode{gt.saved, gt.smart} {
+		rows++
+		if category.IsExpanded() {
+			rows += len(category.GetChildren())
+		}
+	}
+	return min(rows, maxGroupTreeHeight)
+}
+
+func (gt *GroupTree) OnSelect(fn func(search domain.SavedSearch)) *GroupTree {
+	gt.onSelect = fn
+	return gt
+}
+
+func (gt *GroupTree) OnResize(fn func()) *GroupTree {
+	gt.onResize = fn
+	return gt
+}
+
+func (gt *GroupTree) labelCategories() {
+	gt.saved.SetText(categoryLabel("Saved searches", gt.saved))
+	gt.smart.SetText(categoryLabel("Smart groups", gt.smart))
+}
+
+func categoryLabel(name string, node *tview.TreeNode) string {
+	arrow := "▾"
+	if !node.IsExpanded() {
+		arrow = "▸"
+	}
+	if len(node.GetChildren()) == 0 && node.IsExpanded() {
+		return fmt.Sprintf("[#888888]%s %s (none yet: Ctrl+S in the search bar)[-]", arrow, name)
+	}
+	return fmt.Sprintf("[::b]%s %s[-:-:-]", arrow, name)
+}
+
+func newGroupNode(ref groupRef, count int) *tview.TreeNode {
+	text := tview.Escape(ref.search.Name)
+	if count >= 0 {
+		text += fmt.Sprintf(" [#888888](%d)[-]", count)
+	}
+	return tview.NewTreeNode(text).SetReference(ref).SetSelectable(true)
+}
+
+// countAt returns counts[i], or -1 when there is no such count.
+func countAt(counts []int, i int) int {
+	if i < len(counts) {
+		return counts[i]
+	}
+	return -1
+}
@@ -17,9 +17,12 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/services"
+	"github.com/Adembc/lazyssh/internal/fsutil"
 	"github.com/atotto/clipboard"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -34,6 +37,17 @@ func (t *tui) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 	if t.app.GetFocus() == t.searchBar {
 		return event
 	}
+	if t.app.GetFocus() == t.groupTree {
+		return t.handleGroupKeys(event)
+	}
+	switch t.view {
+	case viewProfiles:
+		return t.handleProfileKeys(event)
+	case viewMatchBlocks:
+		return t.handleMatchBlockKeys(event)
+	case viewBackups:
+		return t.handleBackupKeys(event)
+	}
 
 	switch event.Rune() {
 	case 'q':
@@ -54,18 +68,45 @@ func (t *tui) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 	case 'p':
 		t.handleServerPin()
 		return nil
+	case ' ':
+		t.handleMarkToggle()
+		return nil
+	case 'V':
+		t.handleMarkRange()
+		return nil
+	case '*':
+		t.handleMarkAll()
+		return nil
+	case 'f':
+		t.handleSetField()
+		return nil
 	case 's':
-		t.handleSortToggle()
+		t.handleSortMenu()
 		return nil
 	case 'S':
 		t.handleSortReverse()
 		return nil
+	case 'P':
+		t.handleProfilesToggle()
+		return nil
+	case 'M':
+		t.handleMatchBlocksToggle()
+		return nil
+	case 'B':
+		t.handleBackupsToggle()
+		return nil
 	case 'c':
 		t.handleCopyCommand()
 		return nil
 	case 'g':
 		t.handlePingSelected()
 		return nil
+	case 'G':
+		t.handlePingAll()
+		return nil
+	case 'v':
+		t.handleGroupingToggle()
+		return nil
 	case 'r':
 		t.handleRefreshBackground()
 		return nil
@@ -78,13 +119,156 @@ func (t *tui) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 	case 'k':
 		t.handleNavigateUp()
 		return nil
+	case 'u':
+		t.handleUndo()
+		return nil
 	}
 
-	if event.Key() == tcell.KeyEnter {
+	switch event.Key() {
+	case tcell.KeyEnter:
+		if t.grouping != GroupByNone && t.serverTree.ToggleSelectedGroup() {
+			return nil
+		}
 		t.handleServerConnect()
 		return nil
+	case tcell.KeyTab:
+		t.app.SetFocus(t.groupTree)
+		return nil
+	case tcell.KeyCtrlR:
+		t.handleRedo()
+		return nil
+	case tcell.KeyEscape:
+		if len(t.serverList.MarkedAliases()) > 0 {
+			t.handleMarkClear()
+			return nil
+		}
+	}
+
+	return event
+}
+
+// handleProfileKeys handles keys while the Profiles view replaces the server list.
+func (t *tui) handleProfileKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Rune() {
+	case 'q':
+		t.handleQuit()
+		return nil
+	case 'P':
+		t.handleProfilesToggle()
+		return nil
+	case 'a':
+		t.handleProfileAdd()
+		return nil
+	case 'e':
+		t.handleProfileEdit()
+		return nil
+	case 'd':
+		t.handleProfileDelete()
+		return nil
+	case 'u':
+		t.handleUndo()
+		return nil
+	case 'j':
+		t.profileList.SetCurrentItem((t.profileList.GetCurrentItem() + 1) % max(t.profileList.GetItemCount(), 1))
+		return nil
+	case 'k':
+		if idx := t.profileList.GetCurrentItem(); idx > 0 {
+			t.profileList.SetCurrentItem(idx - 1)
+		} else {
+			t.profileList.SetCurrentItem(t.profileList.GetItemCount() - 1)
+		}
+		return nil
+	}
+
+	switch event.Key() {
+	case tcell.KeyEscape:
+		t.handleProfilesToggle()
+		return nil
+	case tcell.KeyEnter:
+		t.handleProfileEdit()
+		return nil
+	case tcell.KeyCtrlR:
+		t.handleRedo()
+		return nil
+	}
+	return event
+}
+
+// handleMatchBlockKeys handles keys while the read-only Match blocks view replaces the server list.
+func (t *tui) handleMatchBlockKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Rune() {
+	case 'q':
+		t.handleQuit()
+		return nil
+	case 'M':
+		t.handleMatchBlocksToggle()
+		return nil
+	case 'j':
+		t.matchBlockList.SetCurrentItem((t.matchBlockList.GetCurrentItem() + 1) % max(t.matchBlockList.GetItemCount(), 1))
+		return nil
+	case 'k':
+		if idx := t.matchBlockList.GetCurrentItem(); idx > 0 {
+			t.matchBlockList.SetCurrentItem(idx - 1)
+		} else {
+			t.matchBlockList.SetCurrentItem(t.matchBlockList.GetItemCount() - 1)
+		}
+		return nil
+	}
+
+	if event.Key() == tcell.KeyEscape {
+		t.handleMatchBlocksToggle()
+		return nil
 	}
+	return event
+}
+
+func (t *tui) handleBackupKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Rune() {
+	case 'q':
+		t.handleQuit()
+		return nil
+	case 'B':
+		t.handleBackupsToggle()
+		return nil
+	case 'r':
+		t.handleBackupRestore()
+		return nil
+	case 'j':
+		t.backupList.SetCurrentItem((t.backupList.GetCurrentItem() + 1) % max(t.backupList.GetItemCount(), 1))
+		return nil
+	case 'k':
+		if idx := t.backupList.GetCurrentItem(); idx > 0 {
+			t.backupList.SetCurrentItem(idx - 1)
+		} else {
+			t.backupList.SetCurrentItem(t.backupList.GetItemCount() - 1)
+		}
+		return nil
+	}
+
+	if event.Key() == tcell.KeyEscape {
+		t.handleBackupsToggle()
+		return nil
+	}
+	return event
+}
 
+// handleGroupKeys handles keys while the groups sidebar has focus; the tree handles navigation itself.
+func (t *tui) handleGroupKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyTab, tcell.KeyEsc:
+		t.app.SetFocus(t.serverPane())
+		return nil
+	}
+	switch event.Rune() {
+	case 'q':
+		t.handleQuit()
+		return nil
+	case 'd':
+		if search, saved, ok := t.groupTree.GetSelectedGroup(); ok && saved {
+			t.showDeleteSearchConfirmModal(search)
+		}
+		return nil
+	}
 	return event
 }
 
@@ -93,29 +277,96 @@ func (t *tui) handleQuit() {
 }
 
 func (t *tui) handleServerPin() {
-	if server, ok := t.serverList.GetSelectedServer(); ok {
+	if servers := t.markedServers(); len(servers) > 0 {
+		t.handleBulkPin(servers)
+		return
+	}
+	if server, ok := t.selectedServer(); ok {
 		pinned := server.PinnedAt.IsZero()
 		_ = t.serverService.SetPinned(server.Alias, pinned)
 		t.refreshServerList()
 	}
 }
 
-func (t *tui) handleSortToggle() {
-	t.sortMode = t.sortMode.ToggleField()
-	t.showStatusTemp("Sort: " + t.sortMode.String())
-	t.updateListTitle()
+func (t *tui) handleUndo() {
+	description, err := t.serverService.Undo()
+	if err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Undo failed: %v", err), "#FF6B6B")
+		return
+	}
+	t.refreshAfterHistoryChange()
+	t.showStatusTemp("Undid: " + description)
+}
+
+func (t *tui) handleRedo() {
+	description, err := t.serverService.Redo()
+	if err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Redo failed: %v", err), "#FF6B6B")
+		return
+	}
+	t.refreshAfterHistoryChange()
+	t.showStatusTemp("Redid: " + description)
+}
+
+// refreshAfterHistoryChange reloads the lists an undo or redo may have changed.
+func (t *tui) refreshAfterHistoryChange() {
 	t.refreshServerList()
+	if t.view == viewProfiles {
+		t.refreshProfileList()
+	}
+}
+
+func (t *tui) handleSortMenu() {
+	t.showSortMenu()
 }
 
 func (t *tui) handleSortReverse() {
-	t.sortMode = t.sortMode.Reverse()
+	t.setSortMode(t.sortMode.Reverse())
+}
+
+// handleHelpModeChange remembers the help panel mode for later forms and sessions.
+func (t *tui) handleHelpModeChange(mode HelpDisplayMode) {
+	t.helpMode = mode
+	t.saveState()
+}
+
+// setSortMode re-sorts the servers and remembers the mode for the next session.
+func (t *tui) setSortMode(mode services.SortMode) {
+	t.sortMode = mode
 	t.showStatusTemp("Sort: " + t.sortMode.String())
 	t.updateListTitle()
 	t.refreshServerList()
+	t.saveState()
+}
+
+// handleGroupingToggle cycles the server pane through the flat list and the tree groupings.
+func (t *tui) handleGroupingToggle() {
+	selected, hasSelection := t.selectedServer()
+	if len(t.serverList.MarkedAliases()) > 0 {
+		// Marks are only shown in the flat list, so don't let hidden ones drive bulk actions
+		t.serverList.ClearMarks()
+	}
+	t.grouping = t.grouping.Next()
+	t.serverTree.SetGrouping(t.grouping)
+	t.updateListTitle()
+	t.layoutServerPane()
+	t.refreshServerList()
+	if hasSelection && t.selectServer(selected.Alias) {
+		t.details.UpdateServer(selected)
+	}
+	if !t.searchVisible {
+		t.app.SetFocus(t.serverPane())
+	}
+	t.saveState()
+	if t.grouping == GroupByNone {
+		t.showStatusTemp("View: list")
+	} else {
+		t.showStatusTemp("View: grouped by " + t.grouping.String())
+	}
 }
 
 func (t *tui) handleCopyCommand() {
-	if server, ok := t.serverList.GetSelectedServer(); ok {
+	if server, ok := t.selectedServer(); ok {
 		cmd := BuildSSHCommand(server)
 		if err := clipboard.WriteAll(cmd); err == nil {
 			t.showStatusTemp("Copied: " + cmd)
@@ -126,12 +377,20 @@ func (t *tui) handleCopyCommand() {
 }
 
 func (t *tui) handleTagsEdit() {
-	if server, ok := t.serverList.GetSelectedServer(); ok {
+	if servers := t.markedServers(); len(servers) > 0 {
+		t.showBulkTagsForm(servers)
+		return
+	}
+	if server, ok := t.selectedServer(); ok {
 		t.showEditTagsForm(server)
 	}
 }
 
 func (t *tui) handleNavigateDown() {
+	if t.app.GetFocus() == t.serverTree {
+		t.serverTree.Move(1)
+		return
+	}
 	if t.app.GetFocus() == t.serverList {
 		currentIdx := t.serverList.GetCurrentItem()
 		itemCount := t.serverList.GetItemCount()
@@ -144,6 +403,10 @@ func (t *tui) handleNavigateDown() {
 }
 
 func (t *tui) handleNavigateUp() {
+	if t.app.GetFocus() == t.serverTree {
+		t.serverTree.Move(-1)
+		return
+	}
 	if t.app.GetFocus() == t.serverList {
 		currentIdx := t.serverList.GetCurrentItem()
 		if currentIdx > 0 {
@@ -155,20 +418,40 @@ func (t *tui) handleNavigateUp() {
 }
 
 func (t *tui) handleSearchInput(query string) {
-	filtered, _ := t.serverService.ListServers(query)
-	sortServersForUI(filtered, t.sortMode)
-	t.serverList.UpdateServers(filtered)
+	filtered, _ := t.listServers(query)
+	t.showServerResults(filtered)
 	if len(filtered) == 0 {
 		t.details.ShowEmpty()
 	}
 }
 
+// handleGroupSelect shows the servers of a group by searching for its query.
+func (t *tui) handleGroupSelect(search domain.SavedSearch) {
+	t.showSearchBar()
+	t.searchBar.SetText(search.Query)
+	t.app.SetFocus(t.serverPane())
+}
+
+func (t *tui) handleGroupResize() {
+	if t.view == viewServers {
+		t.left.ResizeItem(t.groupTree, t.groupTree.Height(), 0)
+	}
+}
+
+func (t *tui) handleSearchSave(query string) {
+	if strings.TrimSpace(query) == "" {
+		t.showStatusTempColor("Type a search before saving it as a group", "#FFD75F")
+		return
+	}
+	t.showSaveSearchForm(query)
+}
+
 func (t *tui) handleSearchToggle() {
 	t.showSearchBar()
 }
 
 func (t *tui) handleServerConnect() {
-	if server, ok := t.serverList.GetSelectedServer(); ok {
+	if server, ok := t.selectedServer(); ok {
 
 		t.app.Suspend(func() {
 			_ = t.serverService.SSH(server.Alias)
@@ -178,30 +461,150 @@ func (t *tui) handleServerConnect() {
 }
 
 func (t *tui) handleServerSelectionChange(server domain.Server) {
+	// The details pane belongs to the Profiles or Match view while one is shown
+	if t.view != viewServers {
+		return
+	}
 	t.details.UpdateServer(server)
 }
 
+func (t *tui) handleProfileSelectionChange(profile domain.Profile) {
+	t.details.UpdateProfile(profile)
+}
+
+func (t *tui) handleProfilesToggle() {
+	if t.view == viewProfiles {
+		t.showServers()
+		return
+	}
+	t.showProfiles()
+}
+
+func (t *tui) handleMatchBlockSelectionChange(block domain.MatchBlock) {
+	t.details.UpdateMatchBlock(block)
+}
+
+func (t *tui) handleMatchBlocksToggle() {
+	if t.view == viewMatchBlocks {
+		t.showServers()
+		return
+	}
+	t.showMatchBlocks()
+}
+
+func (t *tui) handleBackupSelectionChange(backup domain.Backup) {
+	diff, err := t.serverService.BackupDiff(backup)
+	t.details.UpdateBackup(backup, diff, err)
+}
+
+func (t *tui) handleBackupsToggle() {
+	if t.view == viewBackups {
+		t.showServers()
+		return
+	}
+	t.showBackups()
+}
+
+func (t *tui) handleBackupRestore() {
+	if backup, ok := t.backupList.GetSelectedBackup(); ok {
+		t.showRestoreBackupConfirmModal(backup)
+	}
+}
+
+func (t *tui) handleProfileAdd() {
+	files, _ := t.serverService.ListConfigFiles()
+	lastFile, _ := t.serverService.LastConfigFile()
+	form := NewServerForm(ServerFormAdd, nil).
+		SetApp(t.app).
+		SetHelpMode(t.helpMode).
+		OnHelpModeChange(t.handleHelpModeChange).
+		AsProfile().
+		SetConfigFiles(files, lastFile).
+		SetVersionInfo(t.version, t.commit).
+		OnSave(t.handleProfileSave).
+		OnCancel(t.handleFormCancel)
+	t.showForm(form)
+}
+
+func (t *tui) handleProfileEdit() {
+	if profile, ok := t.profileList.GetSelectedProfile(); ok {
+		original := profile.Server
+		form := NewServerForm(ServerFormEdit, &original).
+			SetApp(t.app).
+			SetHelpMode(t.helpMode).
+			OnHelpModeChange(t.handleHelpModeChange).
+			AsProfile().
+			SetVersionInfo(t.version, t.commit).
+			OnSave(t.handleProfileSave).
+			OnCancel(t.handleFormCancel)
+		t.showForm(form)
+	}
+}
+
+func (t *tui) handleProfileSave(server domain.Server, original *domain.Server) {
+	if !t.confirmSaveIfChanged(func() { t.handleProfileSave(server, original) }) {
+		return
+	}
+
+	var err error
+	if original != nil {
+		err = t.serverService.UpdateProfile(domain.Profile{Server: *original}, domain.Profile{Server: server})
+	} else {
+		err = t.serverService.AddProfile(domain.Profile{Server: server})
+	}
+	if err != nil {
+		modal := tview.NewModal().
+			SetText(fmt.Sprintf("Save failed: %v", err)).
+			AddButtons([]string{"Close"}).
+			SetDoneFunc(func(buttonIndex int, buttonLabel string) { t.handleModalClose() })
+		t.app.SetRoot(modal, true)
+		return
+	}
+
+	// Profiles feed the effective configuration of servers, so refresh both lists
+	t.refreshProfileList()
+	t.refreshServerList()
+	t.handleFormCancel()
+}
+
+func (t *tui) handleProfileDelete() {
+	if profile, ok := t.profileList.GetSelectedProfile(); ok {
+		t.showDeleteProfileConfirmModal(profile)
+	}
+}
+
 func (t *tui) handleServerAdd() {
+	files, _ := t.serverService.ListConfigFiles()
+	lastFile, _ := t.serverService.LastConfigFile()
 	form := NewServerForm(ServerFormAdd, nil).
 		SetApp(t.app).
+		SetHelpMode(t.helpMode).
+		OnHelpModeChange(t.handleHelpModeChange).
+		SetConfigFiles(files, lastFile).
 		SetVersionInfo(t.version, t.commit).
 		OnSave(t.handleServerSave).
 		OnCancel(t.handleFormCancel)
-	t.app.SetRoot(form, true)
+	t.showForm(form)
 }
 
 func (t *tui) handleServerEdit() {
-	if server, ok := t.serverList.GetSelectedServer(); ok {
+	if server, ok := t.selectedServer(); ok {
 		form := NewServerForm(ServerFormEdit, &server).
 			SetApp(t.app).
+			SetHelpMode(t.helpMode).
+			OnHelpModeChange(t.handleHelpModeChange).
 			SetVersionInfo(t.version, t.commit).
 			OnSave(t.handleServerSave).
 			OnCancel(t.handleFormCancel)
-		t.app.SetRoot(form, true)
+		t.showForm(form)
 	}
 }
 
 func (t *tui) handleServerSave(server domain.Server, original *domain.Server) {
+	if !t.confirmSaveIfChanged(func() { t.handleServerSave(server, original) }) {
+		return
+	}
+
 	var err error
 	if original != nil {
 		// Edit mode
@@ -225,73 +628,340 @@ func (t *tui) handleServerSave(server domain.Server, original *domain.Server) {
 }
 
 func (t *tui) handleServerDelete() {
-	if server, ok := t.serverList.GetSelectedServer(); ok {
+	if servers := t.markedServers(); len(servers) > 0 {
+		t.showBulkDeleteConfirmModal(servers)
+		return
+	}
+	if server, ok := t.selectedServer(); ok {
 		t.showDeleteConfirmModal(server)
 	}
 }
 
-func (t *tui) handleFormCancel() {
-	t.returnToMain()
+// showForm opens an add/edit form and remembers the config version it was opened on.
+func (t *tui) showForm(form *ServerForm) {
+	t.form = form
+	t.formVersion = t.serverService.ConfigVersion()
+	t.app.SetRoot(form, true)
 }
 
-func (t *tui) handlePingSelected() {
-	if server, ok := t.serverList.GetSelectedServer(); ok {
-		alias := server.Alias
+// confirmSaveIfChanged reports whether the open form may be saved right away. If the config
+// or metadata changed on disk after the form was opened, it asks first and calls save once
+// the user confirms.
+func (t *tui) confirmSaveIfChanged(save func()) bool {
+	current := t.serverService.ConfigVersion()
+	if t.form == nil || current == t.formVersion {
+		return true
+	}
 
-		t.showStatusTemp(fmt.Sprintf("Pinging %s…", alias))
-		go func() {
-			up, dur, err := t.serverService.Ping(server)
-			t.app.QueueUpdateDraw(func() {
-				if err != nil {
-					t.showStatusTempColor(fmt.Sprintf("Ping %s: DOWN (%v)", alias, err), "#FF6B6B")
-					return
-				}
-				if up {
-					t.showStatusTempColor(fmt.Sprintf("Ping %s: UP (%s)", alias, dur), "#A0FFA0")
-				} else {
-					t.showStatusTempColor(fmt.Sprintf("Ping %s: DOWN", alias), "#FF6B6B")
-				}
-			})
-		}()
+	saveAnyway := func() {
+		t.formVersion = current
+		save()
+	}
+	backToForm := func() {
+		t.app.SetRoot(t.form, true)
 	}
-}
 
-func (t *tui) handleModalClose() {
-	t.returnToMain()
+	modal := tview.NewModal().
+		SetText("The SSH config changed on disk after this form was opened.\n\nSaving may overwrite those changes to this entry.").
+		AddButtons([]string{"[yellow]B[-]ack to form", "[yellow]S[-]ave anyway"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				saveAnyway()
+				return
+			}
+			backToForm()
+		})
+
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'b', 'B':
+			backToForm()
+			return nil
+		case 's', 'S':
+			saveAnyway()
+			return nil
+		}
+		return event
+	})
+
+	t.app.SetRoot(modal, true)
+	return false
 }
 
-// handleRefreshBackground refreshes the server list in the background without leaving the current screen.
-// It preserves the current search query and selection, shows transient status, and avoids concurrent runs.
-func (t *tui) handleRefreshBackground() {
-	currentIdx := t.serverList.GetCurrentItem()
-	query := ""
-	if t.searchVisible {
-		query = t.searchBar.InputField.GetText()
+// watchExternalChanges reloads the lists whenever config or metadata files change outside lazyssh.
+func (t *tui) watchExternalChanges(changes <-chan []string) {
+	for paths := range changes {
+		t.app.QueueUpdateDraw(func() {
+			t.handleExternalChange(paths)
+		})
 	}
+}
 
-	t.showStatusTemp("Refreshing…")
+func (t *tui) handleExternalChange(paths []string) {
+	t.logger.Infow("files changed outside lazyssh", "paths", paths)
 
-	go func(prevIdx int, q string) {
-		servers, err := t.serverService.ListServers(q)
+	selected, hasSelection := t.selectedServer()
+	t.refreshServerList()
+	if hasSelection && t.selectServer(selected.Alias) && t.view == viewServers {
+		if server, ok := t.selectedServer(); ok {
+			t.details.UpdateServer(server)
+		}
+	}
+
+	switch t.view {
+	case viewProfiles:
+		idx := t.profileList.GetCurrentItem()
+		t.refreshProfileList()
+		if idx < t.profileList.GetItemCount() {
+			t.profileList.SetCurrentItem(idx)
+		}
+	case viewMatchBlocks:
+		idx := t.matchBlockList.GetCurrentItem()
+		blocks, _ := t.serverService.ListMatchBlocks()
+		t.matchBlockList.UpdateMatchBlocks(blocks)
+		if idx < t.matchBlockList.GetItemCount() {
+			t.matchBlockList.SetCurrentItem(idx)
+		}
+	case viewBackups:
+		idx := t.backupList.GetCurrentItem()
+		t.refreshBackupList()
+		if idx < t.backupList.GetItemCount() {
+			t.backupList.SetCurrentItem(idx)
+		}
+	}
+
+	names := make([]string, len(paths))
+	for i, path := range paths {
+		names[i] = fsutil.ShortenHome(path)
+	}
+	t.showStatusTempColor("Reloaded: "+strings.Join(names, ", ")+" changed on disk", "#FFD75F")
+
+	// A Host renamed in an editor leaves its metadata behind; offer to move it, but
+	// only from the server list so an open form or dialog isn't replaced.
+	if t.form == nil && t.app.GetFocus() == t.serverPane() {
+		t.checkOrphanedMetadata()
+	}
+}
+
+func (t *tui) handleMarkToggle() {
+	if t.canMark() {
+		t.serverList.ToggleMark()
+		t.handleMarksChanged()
+	}
+}
+
+func (t *tui) handleMarkRange() {
+	if t.canMark() {
+		t.serverList.MarkRange()
+		t.handleMarksChanged()
+	}
+}
+
+func (t *tui) handleMarkAll() {
+	if t.canMark() {
+		t.serverList.MarkAll()
+		t.handleMarksChanged()
+	}
+}
+
+func (t *tui) handleMarkClear() {
+	t.serverList.ClearMarks()
+	t.handleMarksChanged()
+}
+
+// canMark reports whether servers can be marked, which is only in the flat list.
+func (t *tui) canMark() bool {
+	if t.grouping != GroupByNone {
+		t.showStatusTempColor("Marking works in the list view; press v to switch back to it", "#FFD75F")
+		return false
+	}
+	return true
+}
+
+func (t *tui) handleMarksChanged() {
+	t.updateListTitle()
+	if n := len(t.serverList.MarkedAliases()); n > 0 {
+		t.showStatusTemp(fmt.Sprintf("%d marked: t Tags • p Pin • f Set field • d Delete • g Ping • Esc Unmark", n))
+	}
+}
+
+func (t *tui) handleSetField() {
+	servers := t.markedServers()
+	if len(servers) == 0 {
+		server, ok := t.selectedServer()
+		if !ok {
+			return
+		}
+		servers = []domain.Server{server}
+	}
+	t.showSetFieldForm(servers)
+}
+
+// handleBulkPin pins the marked servers, or unpins them if they all are pinned already.
+func (t *tui) handleBulkPin(servers []domain.Server) {
+	pinned := false
+	aliases := make([]string, 0, len(servers))
+	for _, server := range servers {
+		pinned = pinned || server.PinnedAt.IsZero()
+		aliases = append(aliases, server.Alias)
+	}
+	if err := t.serverService.SetPinnedServers(aliases, pinned); err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Pin failed: %v", err), "#FF6B6B")
+		return
+	}
+	t.refreshServerList()
+	if pinned {
+		t.showStatusTemp(fmt.Sprintf("Pinned %d servers", len(servers)))
+	} else {
+		t.showStatusTemp(fmt.Sprintf("Unpinned %d servers", len(servers)))
+	}
+}
+
+// handleBulkUpdate saves the changed servers as one change and reports the outcome.
+func (t *tui) handleBulkUpdate(servers, newServers []domain.Server, done string) {
+	if err := t.serverService.UpdateServers(servers, newServers); err != nil {
+		t.returnToMain()
+		t.showStatusTempColor(fmt.Sprintf("Update failed: %v", err), "#FF6B6B")
+		return
+	}
+	t.refreshServerList()
+	t.returnToMain()
+	t.showStatusTemp(done)
+}
+
+// markedServers returns the marked servers that still exist, in list order.
+func (t *tui) markedServers() []domain.Server {
+	aliases := t.serverList.MarkedAliases()
+	if len(aliases) == 0 {
+		return nil
+	}
+	marked := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		marked[alias] = true
+	}
+	all, err := t.serverService.ListServers("")
+	if err != nil {
+		return nil
+	}
+	services.SortServers(all, t.sortMode)
+	servers := make([]domain.Server, 0, len(aliases))
+	for _, server := range all {
+		if marked[server.Alias] {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
+
+func (t *tui) handleFormCancel() {
+	t.form = nil
+	t.returnToMain()
+}
+
+func (t *tui) handlePingSelected() {
+	if servers := t.markedServers(); len(servers) > 0 {
+		t.pingServers(servers)
+		return
+	}
+	if server, ok := t.selectedServer(); ok {
+		alias := server.Alias
+
+		t.showStatusTemp(fmt.Sprintf("Pinging %s…", alias))
+		go func() {
+			up, dur, err := t.serverService.Ping(server)
+			t.app.QueueUpdateDraw(func() {
+				if err != nil {
+					t.showStatusTempColor(fmt.Sprintf("Ping %s: DOWN (%v)", alias, err), "#FF6B6B")
+					return
+				}
+				if up {
+					t.showStatusTempColor(fmt.Sprintf("Ping %s: UP (%s)", alias, dur), "#A0FFA0")
+				} else {
+					t.showStatusTempColor(fmt.Sprintf("Ping %s: DOWN", alias), "#FF6B6B")
+				}
+			})
+		}()
+	}
+}
+
+// handlePingAll pings every server in the background, a few at a time, so the Unreachable
+// group and the status: qualifier reflect the whole list.
+func (t *tui) handlePingAll() {
+	servers, err := t.serverService.ListServers("")
+	if err != nil || len(servers) == 0 {
+		return
+	}
+	t.pingServers(servers)
+}
+
+// pingServers pings servers in the background, 8 at a time, and reports how many are down.
+func (t *tui) pingServers(servers []domain.Server) {
+	t.showStatusTemp(fmt.Sprintf("Pinging %d servers…", len(servers)))
+	go func() {
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		down := 0
+		limit := make(chan struct{}, 8)
+		for _, server := range servers {
+			wg.Add(1)
+			limit <- struct{}{}
+			go func(server domain.Server) {
+				defer wg.Done()
+				defer func() { <-limit }()
+				if up, _, _ := t.serverService.Ping(server); !up {
+					mu.Lock()
+					down++
+					mu.Unlock()
+				}
+			}(server)
+		}
+		wg.Wait()
+		t.app.QueueUpdateDraw(func() {
+			t.refreshServerList()
+			msg := fmt.Sprintf("Pinged %d servers: %d down", len(servers), down)
+			if down > 0 {
+				t.showStatusTempColor(msg, "#FF6B6B")
+			} else {
+				t.showStatusTempColor(msg, "#A0FFA0")
+			}
+		})
+	}()
+}
+
+func (t *tui) handleModalClose() {
+	t.returnToMain()
+}
+
+// handleRefreshBackground refreshes the server list in the background without leaving the current screen.
+// It preserves the current search query and selection, shows transient status, and avoids concurrent runs.
+func (t *tui) handleRefreshBackground() {
+	selected, _ := t.selectedServer()
+	query := ""
+	if t.searchVisible {
+		query = t.searchBar.InputField.GetText()
+	}
+
+	t.showStatusTemp("Refreshing…")
+
+	go func(prevAlias string, q string) {
+		servers, err := t.listServers(q)
 		if err != nil {
 			t.app.QueueUpdateDraw(func() {
 				t.showStatusTempColor(fmt.Sprintf("Refresh failed: %v", err), "#FF6B6B")
 			})
 			return
 		}
-		sortServersForUI(servers, t.sortMode)
 		t.app.QueueUpdateDraw(func() {
-			t.serverList.UpdateServers(servers)
-			// Try to restore selection if still valid
-			if prevIdx >= 0 && prevIdx < t.serverList.List.GetItemCount() {
-				t.serverList.SetCurrentItem(prevIdx)
-				if srv, ok := t.serverList.GetSelectedServer(); ok {
+			t.showServerResults(servers)
+			// Try to restore selection if still listed
+			if prevAlias != "" && t.selectServer(prevAlias) {
+				if srv, ok := t.selectedServer(); ok {
 					t.details.UpdateServer(srv)
 				}
 			}
 			t.showStatusTemp(fmt.Sprintf("Refreshed %d servers", len(servers)))
 		})
-	}(currentIdx, query)
+	}(selected.Alias, query)
 }
 
 // =============================================================================
@@ -299,11 +969,21 @@ func (t *tui) handleRefreshBackground() {
 // =============================================================================
 
 func (t *tui) showSearchBar() {
-	t.left.Clear()
-	t.left.AddItem(t.searchBar, 3, 0, true)
-	t.left.AddItem(t.serverList, 0, 1, false)
-	t.app.SetFocus(t.searchBar)
 	t.searchVisible = true
+	t.layoutServerPane()
+	t.app.SetFocus(t.searchBar)
+}
+
+// layoutServerPane fills the left pane with the hint or search bar, the groups sidebar and the server list.
+func (t *tui) layoutServerPane() {
+	t.left.Clear()
+	if t.searchVisible {
+		t.left.AddItem(t.searchBar, 3, 0, true)
+	} else {
+		t.left.AddItem(t.hintBar, 1, 0, false)
+	}
+	t.left.AddItem(t.groupTree, t.groupTree.Height(), 0, false)
+	t.left.AddItem(t.serverPane(), 0, 1, !t.searchVisible)
 }
 
 func (t *tui) showDeleteConfirmModal(server domain.Server) {
@@ -342,6 +1022,494 @@ func (t *tui) showDeleteConfirmModal(server domain.Server) {
 	t.app.SetRoot(modal, true)
 }
 
+// showBulkDeleteConfirmModal deletes the marked servers after a single confirmation.
+func (t *tui) showBulkDeleteConfirmModal(servers []domain.Server) {
+	aliases := make([]string, 0, len(servers))
+	for i, server := range servers {
+		if i == 10 {
+			aliases = append(aliases, fmt.Sprintf("and %d more", len(servers)-i))
+			break
+		}
+		aliases = append(aliases, server.Alias)
+	}
+	msg := fmt.Sprintf("Delete %d servers?\n\n%s\n\nOne backup is taken first, and u undoes the whole deletion.",
+		len(servers), strings.Join(aliases, ", "))
+
+	deleteAll := func() {
+		if err := t.serverService.DeleteServers(servers); err != nil {
+			t.handleModalClose()
+			t.showStatusTempColor(fmt.Sprintf("Delete failed: %v", err), "#FF6B6B")
+			return
+		}
+		t.serverList.ClearMarks()
+		t.refreshServerList()
+		t.updateListTitle()
+		t.handleModalClose()
+		t.showStatusTemp(fmt.Sprintf("Deleted %d servers", len(servers)))
+	}
+	modal := tview.NewModal().
+		SetText(msg).
+		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]D[-]elete"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				deleteAll()
+				return
+			}
+			t.handleModalClose()
+		})
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c', 'C':
+			t.handleModalClose()
+			return nil
+		case 'd', 'D':
+			deleteAll()
+			return nil
+		}
+		return event
+	})
+
+	t.app.SetRoot(modal, true)
+}
+
+func (t *tui) showProfiles() {
+	t.showListView(viewProfiles, t.profileList)
+	t.refreshProfileList()
+	if t.profileList.GetItemCount() == 0 {
+		t.details.ShowNoProfiles()
+	}
+	t.showStatusTemp("Profiles: a Add • e Edit • d Delete • P/Esc Back")
+}
+
+func (t *tui) showMatchBlocks() {
+	t.showListView(viewMatchBlocks, t.matchBlockList)
+	blocks, err := t.serverService.ListMatchBlocks()
+	if err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Failed to load Match blocks: %v", err), "#FF6B6B")
+	}
+	t.matchBlockList.UpdateMatchBlocks(blocks)
+	if len(blocks) == 0 {
+		t.details.ShowNoMatchBlocks()
+	}
+	t.showStatusTemp("Match blocks are read-only • M/Esc Back")
+}
+
+// showListView replaces the server list in the left pane with list.
+func (t *tui) showBackups() {
+	t.showListView(viewBackups, t.backupList)
+	t.refreshBackupList()
+	t.showStatusTemp("r Restore selected backup • B/Esc Back")
+}
+
+func (t *tui) refreshBackupList() {
+	backups, err := t.serverService.ListBackups()
+	if err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Failed to load backups: %v", err), "#FF6B6B")
+	}
+	t.backupList.UpdateBackups(backups)
+	if len(backups) == 0 {
+		t.details.ShowNoBackups()
+	}
+}
+
+func (t *tui) showListView(view listView, list tview.Primitive) {
+	if t.searchVisible {
+		t.hideSearchBar()
+	}
+	t.left.Clear()
+	t.left.AddItem(t.hintBar, 1, 0, false)
+	t.left.AddItem(list, 0, 1, true)
+	t.view = view
+	t.app.SetFocus(list)
+}
+
+func (t *tui) showDeleteProfileConfirmModal(profile domain.Profile) {
+	msg := fmt.Sprintf("Delete profile Host %s?\n\nIt currently applies to %d server(s).", profile.Alias, len(profile.Matches))
+
+	deleteProfile := func() {
+		if err := t.serverService.DeleteProfile(profile); err != nil {
+			t.showStatusTempColor(fmt.Sprintf("Delete failed: %v", err), "#FF6B6B")
+		}
+		t.refreshProfileList()
+		t.refreshServerList()
+		t.handleModalClose()
+	}
+
+	modal := tview.NewModal().
+		SetText(msg).
+		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]D[-]elete"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				deleteProfile()
+				return
+			}
+			t.handleModalClose()
+		})
+
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c', 'C':
+			t.handleModalClose()
+			return nil
+		case 'd', 'D':
+			deleteProfile()
+			return nil
+		}
+		return event
+	})
+
+	t.app.SetRoot(modal, true)
+}
+
+func (t *tui) showRestoreBackupConfirmModal(backup domain.Backup) {
+	msg := fmt.Sprintf("Restore %s from the backup taken %s?\n\nThe current file is backed up first, and u undoes the restore.",
+		fsutil.ShortenHome(backup.ConfigFile), backup.CreatedAt.Format("2006-01-02 15:04:05"))
+
+	restore := func() {
+		t.handleModalClose()
+		if err := t.serverService.RestoreBackup(backup); err != nil {
+			t.showStatusTempColor(fmt.Sprintf("Restore failed: %v", err), "#FF6B6B")
+			return
+		}
+		t.refreshServerList()
+		t.refreshBackupList()
+		t.showStatusTemp("Restored " + fsutil.ShortenHome(backup.ConfigFile))
+	}
+
+	modal := tview.NewModal().
+		SetText(msg).
+		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]R[-]estore"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				restore()
+				return
+			}
+			t.handleModalClose()
+		})
+
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c', 'C':
+			t.handleModalClose()
+			return nil
+		case 'r', 'R':
+			restore()
+			return nil
+		}
+		return event
+	})
+
+	t.app.SetRoot(modal, true)
+}
+
+// checkOrphanedMetadata offers to migrate or purge metadata whose server is no longer in the config.
+func (t *tui) checkOrphanedMetadata() {
+	orphans, err := t.serverService.ReconcileMetadata()
+	if err != nil {
+		return
+	}
+	pending := make([]domain.MetadataOrphan, 0, len(orphans))
+	for _, orphan := range orphans {
+		if !t.dismissedOrphans[orphan.Alias] {
+			pending = append(pending, orphan)
+		}
+	}
+	if len(pending) > 0 {
+		t.showOrphanedMetadataModal(pending)
+	}
+}
+
+func (t *tui) showOrphanedMetadataModal(orphans []domain.MetadataOrphan) {
+	var b strings.Builder
+	b.WriteString("lazyssh has metadata for servers that are no longer in your SSH config:\n\n")
+	matched := 0
+	for _, orphan := range orphans {
+		if orphan.MatchedAlias != "" {
+			matched++
+			fmt.Fprintf(&b, "%s → %s (same HostName, User and Port)", orphan.Alias, orphan.MatchedAlias)
+		} else {
+			fmt.Fprintf(&b, "%s (no match)", orphan.Alias)
+		}
+		if details := describeOrphan(orphan); details != "" {
+			b.WriteString(": " + details)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\nMigrate moves each matched entry to its new alias; Purge deletes them all.")
+
+	keep := func() {
+		for _, orphan := range orphans {
+			t.dismissedOrphans[orphan.Alias] = true
+		}
+		t.handleModalClose()
+	}
+	migrate := func() {
+		moved := 0
+		for _, orphan := range orphans {
+			if orphan.MatchedAlias == "" {
+				continue
+			}
+			if err := t.serverService.MigrateMetadata(orphan.Alias, orphan.MatchedAlias); err != nil {
+				t.handleModalClose()
+				t.showStatusTempColor(fmt.Sprintf("Migrate failed: %v", err), "#FF6B6B")
+				return
+			}
+			moved++
+		}
+		keep()
+		t.refreshServerList()
+		t.showStatusTemp(fmt.Sprintf("Moved metadata of %d server(s)", moved))
+	}
+	purge := func() {
+		t.handleModalClose()
+		for _, orphan := range orphans {
+			if err := t.serverService.PurgeMetadata(orphan.Alias); err != nil {
+				t.showStatusTempColor(fmt.Sprintf("Purge failed: %v", err), "#FF6B6B")
+				return
+			}
+		}
+		t.refreshServerList()
+		t.showStatusTemp(fmt.Sprintf("Purged metadata of %d server(s)", len(orphans)))
+	}
+
+	buttons := []string{"[yellow]L[-]ater", "[yellow]P[-]urge"}
+	actions := []func(){keep, purge}
+	if matched > 0 {
+		buttons = append(buttons, "[yellow]M[-]igrate")
+		actions = append(actions, migrate)
+	}
+
+	modal := tview.NewModal().
+		SetText(b.String()).
+		AddButtons(buttons).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex >= 0 && buttonIndex < len(actions) {
+				actions[buttonIndex]()
+				return
+			}
+			keep()
+		})
+
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'l', 'L':
+			keep()
+			return nil
+		case 'p', 'P':
+			purge()
+			return nil
+		case 'm', 'M':
+			if matched > 0 {
+				migrate()
+				return nil
+			}
+		}
+		return event
+	})
+
+	t.app.SetRoot(modal, true)
+}
+
+// describeOrphan summarizes what an orphaned metadata entry holds, e.g. "tags prod, db; pinned; 12 connections".
+func describeOrphan(orphan domain.MetadataOrphan) string {
+	parts := make([]string, 0, 3)
+	if len(orphan.Tags) > 0 {
+		parts = append(parts, "tags "+strings.Join(orphan.Tags, ", "))
+	}
+	if orphan.Pinned {
+		parts = append(parts, "pinned")
+	}
+	if orphan.SSHCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d connections", orphan.SSHCount))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (t *tui) showSaveSearchForm(query string) {
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(" Save Search as Group ").
+		SetTitleAlign(tview.AlignCenter)
+
+	form.AddTextView("Query:", tview.Escape(query), 40, 1, true, false)
+	form.AddInputField("Name:", "", 40, nil, nil)
+
+	form.AddButton("Save", func() {
+		name := strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+		if err := t.serverService.SaveSearch(domain.SavedSearch{Name: name, Query: query}); err != nil {
+			t.returnToMain()
+			t.showStatusTempColor(fmt.Sprintf("Save failed: %v", err), "#FF6B6B")
+			return
+		}
+		t.returnToMain()
+		t.refreshGroups()
+		t.showStatusTemp("Saved search " + name)
+	})
+	form.AddButton("Cancel", func() { t.returnToMain() })
+	form.SetCancelFunc(func() { t.returnToMain() })
+	form.SetFocus(1)
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+func (t *tui) showDeleteSearchConfirmModal(search domain.SavedSearch) {
+	msg := fmt.Sprintf("Delete saved search %s (%s)?", search.Name, search.Query)
+
+	remove := func() {
+		t.handleModalClose()
+		if err := t.serverService.DeleteSavedSearch(search.Name); err != nil {
+			t.showStatusTempColor(fmt.Sprintf("Delete failed: %v", err), "#FF6B6B")
+			return
+		}
+		t.refreshGroups()
+		t.app.SetFocus(t.groupTree)
+		t.showStatusTemp("Deleted saved search " + search.Name)
+	}
+
+	modal := tview.NewModal().
+		SetText(msg).
+		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]D[-]elete"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				remove()
+				return
+			}
+			t.handleModalClose()
+		})
+
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c', 'C':
+			t.handleModalClose()
+			return nil
+		case 'd', 'D':
+			remove()
+			return nil
+		}
+		return event
+	})
+
+	t.app.SetRoot(modal, true)
+}
+
+// showSortMenu lists the sort fields; choosing the current field again reverses its direction.
+func (t *tui) showSortMenu() {
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true).
+		SetTitle(" Sort by ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	list.SetSelectedBackgroundColor(tcell.Color24).
+		SetSelectedTextColor(tcell.Color255)
+
+	for i, field := range services.SortFields() {
+		label := field.String()
+		if field == t.sortMode.Field {
+			label = t.sortMode.String() + "  [#888888](again to reverse)[-]"
+			defer list.SetCurrentItem(i)
+		}
+		list.AddItem(label, "", rune('1'+i), func() {
+			t.returnToMain()
+			if field == t.sortMode.Field {
+				t.setSortMode(t.sortMode.Reverse())
+				return
+			}
+			t.setSortMode(services.NewSortMode(field))
+		})
+	}
+	list.SetDoneFunc(t.returnToMain)
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		case 'q':
+			t.returnToMain()
+			return nil
+		}
+		return event
+	})
+
+	menu := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(list, len(services.SortFields())+2, 0, true).
+			AddItem(nil, 0, 1, false), 44, 0, true).
+		AddItem(nil, 0, 1, false)
+	t.app.SetRoot(menu, true)
+}
+
+// showBulkTagsForm adds and removes tags across the marked servers.
+func (t *tui) showBulkTagsForm(servers []domain.Server) {
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Edit Tags: %d servers ", len(servers))).
+		SetTitleAlign(tview.AlignCenter)
+
+	form.AddInputField("Add tags (comma):", "", 40, nil, nil)
+	form.AddInputField("Remove tags (comma):", "", 40, nil, nil)
+
+	form.AddButton("Save", func() {
+		add := parseTagList(form.GetFormItem(0).(*tview.InputField).GetText())
+		remove := parseTagList(form.GetFormItem(1).(*tview.InputField).GetText())
+		newServers := make([]domain.Server, len(servers))
+		for i, server := range servers {
+			newServers[i] = server
+			newServers[i].Tags = services.EditTags(server.Tags, add, remove)
+		}
+		t.handleBulkUpdate(servers, newServers, fmt.Sprintf("Tags updated on %d servers", len(servers)))
+	})
+	form.AddButton("Cancel", func() { t.returnToMain() })
+	form.SetCancelFunc(func() { t.returnToMain() })
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+// showSetFieldForm sets one setting, such as User or ProxyJump, on every given server.
+func (t *tui) showSetFieldForm(servers []domain.Server) {
+	form := tview.NewForm()
+	title := " Set field: " + servers[0].Alias + " "
+	if len(servers) > 1 {
+		title = fmt.Sprintf(" Set field: %d servers ", len(servers))
+	}
+	form.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignCenter)
+
+	names := make([]string, len(bulkFields))
+	for i, field := range bulkFields {
+		names[i] = field.name
+	}
+	form.AddDropDown("Field:", names, 0, nil)
+	form.AddInputField("Value (empty removes it):", "", 40, nil, nil)
+
+	form.AddButton("Save", func() {
+		index, _ := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		field := bulkFields[index]
+		value := strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+		newServers := make([]domain.Server, len(servers))
+		for i, server := range servers {
+			newServers[i] = server
+			if err := field.set(&newServers[i], value); err != nil {
+				t.returnToMain()
+				t.showStatusTempColor(err.Error(), "#FF6B6B")
+				return
+			}
+		}
+		t.handleBulkUpdate(servers, newServers, fmt.Sprintf("%s set on %d servers", field.name, len(servers)))
+	})
+	form.AddButton("Cancel", func() { t.returnToMain() })
+	form.SetCancelFunc(func() { t.returnToMain() })
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
 func (t *tui) showEditTagsForm(server domain.Server) {
 	form := tview.NewForm()
 	form.SetBorder(true).
@@ -381,11 +1549,9 @@ func (t *tui) showEditTagsForm(server domain.Server) {
 // =============================================================================
 
 func (t *tui) hideSearchBar() {
-	t.left.Clear()
-	t.left.AddItem(t.hintBar, 1, 0, false)
-	t.left.AddItem(t.serverList, 0, 1, true)
-	t.app.SetFocus(t.serverList)
 	t.searchVisible = false
+	t.layoutServerPane()
+	t.app.SetFocus(t.serverPane())
 }
 
 // =============================================================================
@@ -397,9 +1563,131 @@ func (t *tui) refreshServerList() {
 	if t.searchVisible {
 		query = t.searchBar.InputField.GetText()
 	}
-	filtered, _ := t.serverService.ListServers(query)
-	sortServersForUI(filtered, t.sortMode)
-	t.serverList.UpdateServers(filtered)
+	t.lintServers()
+	filtered, _ := t.listServers(query)
+	t.showServerResults(filtered)
+	t.refreshGroups()
+}
+
+// lintServers checks every server for config problems, shown as badges in the server list
+// and listed in the details. Linting reads every config file and resolves the effective
+// config of every server, so it only runs when the config version changed since the last
+// run, and in the background.
+func (t *tui) lintServers() {
+	version := t.serverService.ConfigVersion()
+	if version == t.lintVersion {
+		return
+	}
+	t.lintVersion = version
+	go func() {
+		all, err := t.serverService.Lint()
+		if err != nil {
+			return
+		}
+		findings := findingsByAlias(all)
+		t.app.QueueUpdateDraw(func() {
+			if t.lintVersion != version { // a newer run is on its way
+				return
+			}
+			t.serverList.SetFindings(findings)
+			t.serverTree.SetFindings(findings)
+			t.details.SetFindings(findings)
+			if server, ok := t.selectedServer(); ok && t.view == viewServers {
+				t.details.UpdateServer(server)
+			}
+		})
+	}()
+}
+
+// resolveEffective returns the effective configuration of one server, for the details panel.
+func (t *tui) resolveEffective(alias string) []domain.EffectiveSetting {
+	effective, err := t.serverService.ResolveEffective([]string{alias})
+	if err != nil {
+		return nil
+	}
+	return effective[alias]
+}
+
+// refreshGroups reloads the saved searches and the number of servers in every group.
+func (t *tui) refreshGroups() {
+	saved, _ := t.serverService.ListSavedSearches()
+	queries := make([]string, 0, len(saved)+len(smartGroups))
+	for _, search := range append(saved, smartGroups...) {
+		queries = append(queries, search.Query)
+	}
+	counts, _ := t.serverService.CountServers(queries)
+	t.groupTree.UpdateGroups(saved, counts)
+	t.handleGroupResize()
+}
+
+// listServers returns the servers to show for query: while searching they are ranked by how
+// well they match, otherwise every server is listed in the current sort order.
+func (t *tui) listServers(query string) ([]domain.SearchResult, error) {
+	if strings.TrimSpace(query) != "" {
+		return t.serverService.SearchServers(query)
+	}
+	servers, err := t.serverService.ListServers("")
+	if err != nil {
+		return nil, err
+	}
+	services.SortServers(servers, t.sortMode)
+	results := make([]domain.SearchResult, len(servers))
+	for i := range servers {
+		results[i] = domain.SearchResult{Server: servers[i]}
+	}
+	return results, nil
+}
+
+// serverPane is the primitive listing servers: the tree while grouping, the flat list otherwise.
+func (t *tui) serverPane() tview.Primitive {
+	if t.grouping != GroupByNone {
+		return t.serverTree
+	}
+	return t.serverList
+}
+
+func (t *tui) showServerResults(results []domain.SearchResult) {
+	if t.grouping != GroupByNone {
+		t.serverTree.UpdateResults(results)
+		return
+	}
+	t.serverList.UpdateResults(results)
+}
+
+func (t *tui) selectedServer() (domain.Server, bool) {
+	if t.grouping != GroupByNone {
+		return t.serverTree.GetSelectedServer()
+	}
+	return t.serverList.GetSelectedServer()
+}
+
+// selectServer selects the server with the given alias, reporting whether it is listed.
+func (t *tui) selectServer(alias string) bool {
+	if t.grouping != GroupByNone {
+		return t.serverTree.SelectAlias(alias)
+	}
+	return t.serverList.SelectAlias(alias)
+}
+
+// showServers brings the server list back after the Profiles or Match view.
+func (t *tui) showServers() {
+	t.layoutServerPane()
+	t.view = viewServers
+	t.app.SetFocus(t.serverPane())
+	if server, ok := t.selectedServer(); ok {
+		t.details.UpdateServer(server)
+	} else {
+		t.details.ShowEmpty()
+	}
+}
+
+func (t *tui) refreshProfileList() {
+	profiles, err := t.serverService.ListProfiles()
+	if err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Failed to load profiles: %v", err), "#FF6B6B")
+		return
+	}
+	t.profileList.UpdateProfiles(profiles)
 }
 
 func (t *tui) returnToMain() {
@@ -0,0 +1,44 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// findingsByAlias groups findings by the alias of their server.
+func findingsByAlias(findings []domain.Finding) map[string][]domain.Finding {
+	byAlias := make(map[string][]domain.Finding)
+	for _, f := range findings {
+		byAlias[f.Alias] = append(byAlias[f.Alias], f)
+	}
+	return byAlias
+}
+
+// lintBadge renders the number of findings of a server in the color of the worst one, or
+// an empty string if there are none.
+func lintBadge(findings []domain.Finding) string {
+	if len(findings) == 0 {
+		return ""
+	}
+	for _, f := range findings {
+		if f.Severity == domain.SeverityError {
+			return fmt.Sprintf("[#FF5F5F::b]✖ %d[-:-:-]", len(findings))
+		}
+	}
+	return fmt.Sprintf("[#FFAF00::b]⚠ %d[-:-:-]", len(findings))
+}
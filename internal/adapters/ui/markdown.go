@@ -0,0 +1,111 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+var (
+	markdownHeading = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	markdownBullet  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	markdownRule    = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	markdownLink    = regexp.MustCompile(`^\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// renderMarkdown turns server notes written in Markdown into tview-tagged text. It handles
+// the subset that is useful in a runbook: headings, lists, quotes, rules, fenced code,
+// inline code, emphasis and links. Anything else is shown as written.
+func renderMarkdown(text string) string {
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	inCode := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, fmt.Sprintf("[#FFD75F]  %s[-]", tview.Escape(line)))
+			continue
+		}
+
+		if m := markdownHeading.FindStringSubmatch(line); m != nil {
+			out = append(out, fmt.Sprintf("[#5FAFFF::b]%s[-::-]", renderMarkdownInline(m[2])))
+			continue
+		}
+		if markdownRule.MatchString(line) {
+			out = append(out, "[#5F5F5F]────────────────────[-]")
+			continue
+		}
+		if m := markdownBullet.FindStringSubmatch(line); m != nil {
+			out = append(out, m[1]+"• "+renderMarkdownInline(m[2]))
+			continue
+		}
+		if quote, ok := strings.CutPrefix(strings.TrimLeft(line, " "), ">"); ok {
+			out = append(out, "[#808080]│ "+renderMarkdownInline(strings.TrimPrefix(quote, " "))+"[-]")
+			continue
+		}
+		out = append(out, renderMarkdownInline(line))
+	}
+	return strings.Join(out, "\n")
+}
+
+// renderMarkdownInline renders code spans, emphasis and links within a single line.
+func renderMarkdownInline(line string) string {
+	var sb strings.Builder
+	for i := 0; i < len(line); {
+		rest := line[i:]
+		switch {
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				fmt.Fprintf(&sb, "[#FFD75F]%s[-]", tview.Escape(rest[1:1+end]))
+				i += end + 2
+				continue
+			}
+		case strings.HasPrefix(rest, "**"), strings.HasPrefix(rest, "__"):
+			if end := strings.Index(rest[2:], rest[:2]); end > 0 {
+				fmt.Fprintf(&sb, "[::b]%s[::-]", renderMarkdownInline(rest[2:2+end]))
+				i += end + 4
+				continue
+			}
+		case rest[0] == '*', rest[0] == '_' && (i == 0 || line[i-1] == ' '):
+			// An underscore only opens emphasis at the start of a word, so snake_case stays as is.
+			if end := strings.IndexByte(rest[1:], rest[0]); end > 0 {
+				fmt.Fprintf(&sb, "[::i]%s[::-]", renderMarkdownInline(rest[1:1+end]))
+				i += end + 2
+				continue
+			}
+		case rest[0] == '[':
+			if m := markdownLink.FindStringSubmatch(rest); m != nil {
+				fmt.Fprintf(&sb, "[::u]%s[::-] [#808080](%s)[-]", tview.Escape(m[1]), tview.Escape(m[2]))
+				i += len(m[0])
+				continue
+			}
+		}
+
+		j := i + 1
+		for j < len(line) && !strings.ContainsRune("`*_[", rune(line[j])) {
+			j++
+		}
+		sb.WriteString(tview.Escape(line[i:j]))
+		i = j
+	}
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import "testing"
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "just text", "just text"},
+		{"heading", "## Restart", "[#5FAFFF::b]Restart[-::-]"},
+		{"bullet", "- step one\n  * nested", "• step one\n  • nested"},
+		{"quote", "> careful", "[#808080]│ careful[-]"},
+		{"rule", "---", "[#5F5F5F]────────────────────[-]"},
+		{"bold and italic", "**never** reboot *prod*", "[::b]never[::-] reboot [::i]prod[::-]"},
+		{"code span", "run `systemctl restart nginx`", "run [#FFD75F]systemctl restart nginx[-]"},
+		{"link", "see [runbook](https://wiki/web)", "see [::u]runbook[::-] [#808080](https://wiki/web)[-]"},
+		{"snake_case", "use db_primary_host", "use db_primary_host"},
+		{"tview tags are escaped", "color [red] stays", "color [red[] stays"},
+		{"fenced code", "```\n**not bold**\n```", "[#FFD75F]  **not bold**[-]"},
+		{"unclosed markers", "2 * 3 and `tick", "2 * 3 and `tick"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderMarkdown(tt.in); got != tt.want {
+				t.Errorf("renderMarkdown(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
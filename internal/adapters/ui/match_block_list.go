@@ -0,0 +1,105 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/fsutil"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+type MatchBlockList struct {
+	*tview.List
+	blocks            []domain.MatchBlock
+	onSelectionChange func(domain.MatchBlock)
+}
+
+func NewMatchBlockList() *MatchBlockList {
+	list := &MatchBlockList{
+		List: tview.NewList(),
+	}
+	list.build()
+	return list
+}
+
+func (ml *MatchBlockList) build() {
+	ml.List.ShowSecondaryText(false)
+	ml.List.SetBorder(true).
+		SetTitle(" Match Blocks (read-only) ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	ml.List.
+		SetSelectedBackgroundColor(tcell.Color24).
+		SetSelectedTextColor(tcell.Color255).
+		SetHighlightFullLine(true)
+
+	ml.List.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		if index >= 0 && index < len(ml.blocks) && ml.onSelectionChange != nil {
+			ml.onSelectionChange(ml.blocks[index])
+		}
+	})
+}
+
+func (ml *MatchBlockList) UpdateMatchBlocks(blocks []domain.MatchBlock) {
+	ml.blocks = blocks
+	ml.List.Clear()
+
+	for i := range blocks {
+		ml.List.AddItem(formatMatchBlockLine(blocks[i]), "", 0, nil)
+	}
+
+	if ml.List.GetItemCount() > 0 {
+		ml.List.SetCurrentItem(0)
+		if ml.onSelectionChange != nil {
+			ml.onSelectionChange(ml.blocks[0])
+		}
+	}
+}
+
+func (ml *MatchBlockList) OnSelectionChange(fn func(block domain.MatchBlock)) *MatchBlockList {
+	ml.onSelectionChange = fn
+	return ml
+}
+
+// formatMatchCriteria renders criteria the way they would appear on a Match line.
+func formatMatchCriteria(criteria []domain.MatchCriterion) string {
+	parts := make([]string, 0, len(criteria))
+	for _, c := range criteria {
+		keyword := c.Keyword
+		if c.Negated {
+			keyword = "!" + keyword
+		}
+		switch {
+		case c.Argument == "":
+			parts = append(parts, keyword)
+		case strings.ContainsAny(c.Argument, " \t"):
+			parts = append(parts, fmt.Sprintf("%s %q", keyword, c.Argument))
+		default:
+			parts = append(parts, keyword+" "+c.Argument)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatMatchBlockLine(b domain.MatchBlock) string {
+	icon := cellPad("🔀", 2)
+	return fmt.Sprintf("%s [white::b]Match %s[-] [#888888]%s:%d[-]",
+		icon, tview.Escape(formatMatchCriteria(b.Criteria)), tview.Escape(fsutil.ShortenHome(b.SourceFile)), b.Line)
+}
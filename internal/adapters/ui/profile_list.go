@@ -0,0 +1,96 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/fsutil"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+type ProfileList struct {
+	*tview.List
+	profiles          []domain.Profile
+	onSelectionChange func(domain.Profile)
+}
+
+func NewProfileList() *ProfileList {
+	list := &ProfileList{
+		List: tview.NewList(),
+	}
+	list.build()
+	return list
+}
+
+func (pl *ProfileList) build() {
+	pl.List.ShowSecondaryText(false)
+	pl.List.SetBorder(true).
+		SetTitle(" Profiles ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	pl.List.
+		SetSelectedBackgroundColor(tcell.Color24).
+		SetSelectedTextColor(tcell.Color255).
+		SetHighlightFullLine(true)
+
+	pl.List.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		if index >= 0 && index < len(pl.profiles) && pl.onSelectionChange != nil {
+			pl.onSelectionChange(pl.profiles[index])
+		}
+	})
+}
+
+func (pl *ProfileList) UpdateProfiles(profiles []domain.Profile) {
+	pl.profiles = profiles
+	pl.List.Clear()
+
+	for i := range profiles {
+		pl.List.AddItem(formatProfileLine(profiles[i]), "", 0, nil)
+	}
+
+	if pl.List.GetItemCount() > 0 {
+		pl.List.SetCurrentItem(0)
+		if pl.onSelectionChange != nil {
+			pl.onSelectionChange(pl.profiles[0])
+		}
+	}
+}
+
+func (pl *ProfileList) GetSelectedProfile() (domain.Profile, bool) {
+	idx := pl.List.GetCurrentItem()
+	if idx >= 0 && idx < len(pl.profiles) {
+		return pl.profiles[idx], true
+	}
+	return domain.Profile{}, false
+}
+
+func (pl *ProfileList) OnSelectionChange(fn func(profile domain.Profile)) *ProfileList {
+	pl.onSelectionChange = fn
+	return pl
+}
+
+func formatProfileLine(p domain.Profile) string {
+	icon := cellPad("🧩", 2)
+	servers := "servers"
+	if len(p.Matches) == 1 {
+		servers = "server"
+	}
+	return fmt.Sprintf("%s [white::b]%-20s[-] [#888888]%d %s  %s[-]",
+		icon, tview.Escape(p.Alias), len(p.Matches), servers, tview.Escape(fsutil.ShortenHome(p.SourceFile)))
+}
@@ -23,6 +23,7 @@ type SearchBar struct {
 	*tview.InputField
 	onSearch func(string)
 	onEscape func()
+	onSave   func(string)
 }
 
 func NewSearchBar() *SearchBar {
@@ -37,7 +38,9 @@ func (s *SearchBar) build() {
 	s.InputField.SetLabel(" 🔍 Search: ").
 		SetFieldBackgroundColor(tcell.Color233).
 		SetFieldTextColor(tcell.Color252).
-		SetFieldWidth(30).
+		SetFieldWidth(0).
+		SetPlaceholder("fuzzy text, tag:prod user:root port:2222 host:10.0.* pinned:yes, -tag:legacy").
+		SetPlaceholderTextColor(tcell.Color242).
 		SetBorder(true).
 		SetTitle(" Search ").
 		SetTitleAlign(tview.AlignCenter).
@@ -50,6 +53,14 @@ func (s *SearchBar) build() {
 		}
 	})
 
+	s.InputField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlS && s.onSave != nil {
+			s.onSave(s.InputField.GetText())
+			return nil
+		}
+		return event
+	})
+
 	s.InputField.SetDoneFunc(func(key tcell.Key) {
 		if key == tcell.KeyEsc || key == tcell.KeyEnter {
 			if s.onEscape != nil {
@@ -68,3 +79,9 @@ func (s *SearchBar) OnEscape(fn func()) *SearchBar {
 	s.onEscape = fn
 	return s
 }
+
+// OnSave sets what Ctrl+S does with the current query: save it as a group.
+func (s *SearchBar) OnSave(fn func(string)) *SearchBar {
+	s.onSave = fn
+	return s
+}
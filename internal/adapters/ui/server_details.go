@@ -19,12 +19,15 @@ import (
 	"strings"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/fsutil"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
 type ServerDetails struct {
 	*tview.TextView
+	findings         map[string][]domain.Finding
+	resolveEffective func(alias string) []domain.EffectiveSetting
 }
 
 func NewServerDetails() *ServerDetails {
@@ -57,7 +60,30 @@ func renderTagChips(tags []string) string {
 	return strings.Join(chips, " ")
 }
 
+// renderSettingOrigin builds the colored origin label shown next to an effective setting.
+func renderSettingOrigin(setting domain.EffectiveSetting) string {
+	switch setting.Origin {
+	case domain.OriginOwnBlock:
+		return "[#808080](own block)[-]"
+	case domain.OriginDefault:
+		return "[#5F5F5F](OpenSSH default)[-]"
+	default:
+		location := fmt.Sprintf("%s:%d", fsutil.ShortenHome(setting.File), setting.Line)
+		return fmt.Sprintf("[#87AFD7](%s at %s)[-]", tview.Escape(setting.Block), tview.Escape(location))
+	}
+}
+
+// OnResolveEffective sets how the effective configuration of a server is looked up when the
+// server is shown without it; servers are listed without it since resolving it for all is costly.
+func (sd *ServerDetails) OnResolveEffective(fn func(alias string) []domain.EffectiveSetting) *ServerDetails {
+	sd.resolveEffective = fn
+	return sd
+}
+
 func (sd *ServerDetails) UpdateServer(server domain.Server) {
+	if server.Effective == nil && sd.resolveEffective != nil {
+		server.Effective = sd.resolveEffective(server.Alias)
+	}
 	lastSeen := server.LastSeen.Format("2006-01-02 15:04:05")
 	if server.LastSeen.IsZero() {
 		lastSeen = "Never"
@@ -88,6 +114,59 @@ func (sd *ServerDetails) UpdateServer(server domain.Server) {
 		serverKey, tagsText, pinnedStr,
 		lastSeen, server.SSHCount)
 
+	if findings := sd.findings[server.Alias]; len(findings) > 0 {
+		text += "\n[::b]Problems:[-]\n"
+		for _, f := range findings {
+			color := "#FFAF00"
+			if f.Severity == domain.SeverityError {
+				color = "#FF5F5F"
+			}
+			location := fmt.Sprintf("%s:%d", fsutil.ShortenHome(f.File), f.Line)
+			text += fmt.Sprintf("  [%s]%s[-] %s [#808080](%s)[-]\n", color, f.Severity, tview.Escape(f.Message), tview.Escape(location))
+		}
+	}
+
+	if len(server.Fields) > 0 {
+		text += "\n[::b]Fields:[-]\n"
+		for _, key := range sortedFieldKeys(server.Fields) {
+			text += fmt.Sprintf("  %s: [white]%s[-]\n", tview.Escape(key), tview.Escape(server.Fields[key]))
+		}
+	}
+
+	if strings.TrimSpace(server.Notes) != "" {
+		text += "\n[::b]Notes:[-]\n"
+		for _, line := range strings.Split(renderMarkdown(server.Notes), "\n") {
+			text += "  " + line + "\n"
+		}
+	}
+
+	text += renderAdvancedSettings(server)
+
+	if len(server.Effective) > 0 {
+		text += "\n[::b]Effective Config:[-]\n"
+		for _, setting := range server.Effective {
+			text += fmt.Sprintf("  %s: [white]%s[-] %s\n",
+				setting.Key, tview.Escape(setting.Value), renderSettingOrigin(setting))
+		}
+	}
+
+	if server.SourceFile != "" {
+		text += fmt.Sprintf("\n[::b]Defined in:[-]\n  [white]%s[-]\n", tview.Escape(fsutil.ShortenHome(server.SourceFile)))
+	}
+
+	// Commands list
+	text += "\n[::b]Commands:[-]\n  Enter: SSH connect\n  c: Copy SSH command\n  g: Ping server\n  G: Ping all servers\n  Tab: Groups\n  v: List or tree view\n  r: Refresh list\n  a: Add new server\n  e: Edit entry\n  t: Edit tags\n  d: Delete entry\n  p: Pin/Unpin\n  Space/V/*: Mark\n  f: Set field\n  u: Undo\n  Ctrl+R: Redo\n  P: Profiles\n  M: Match blocks\n  B: Backups"
+
+	sd.TextView.SetText(text)
+}
+
+// SetFindings sets the lint findings by alias, listed with the server from the next UpdateServer on.
+func (sd *ServerDetails) SetFindings(findings map[string][]domain.Finding) {
+	sd.findings = findings
+}
+
+// renderAdvancedSettings lists the non-empty advanced settings of a Host block.
+func renderAdvancedSettings(server domain.Server) string {
 	// Advanced settings section (only show non-empty fields)
 	// Organized by logical grouping for better readability
 	type fieldEntry struct {
@@ -208,16 +287,126 @@ func (sd *ServerDetails) UpdateServer(server domain.Server) {
 		}
 	}
 
-	if hasAdvanced {
-		text += advancedText
+	if !hasAdvanced {
+		return ""
+	}
+	return advancedText
+}
+
+// UpdateProfile shows the settings of a pattern Host block and the servers it applies to.
+func (sd *ServerDetails) UpdateProfile(profile domain.Profile) {
+	portText := ""
+	if profile.Port != 0 {
+		portText = fmt.Sprintf("%d", profile.Port)
 	}
+	text := fmt.Sprintf(
+		"[::b]Host %s[-]\n\n[::b]Basic Settings:[-]\n  Host: [white]%s[-]\n  User: [white]%s[-]\n  Port: [white]%s[-]\n  Key:  [white]%s[-]\n",
+		tview.Escape(profile.Alias), tview.Escape(profile.Host), profile.User, portText,
+		strings.Join(profile.IdentityFiles, ", "))
 
-	// Commands list
-	text += "\n[::b]Commands:[-]\n  Enter: SSH connect\n  c: Copy SSH command\n  g: Ping server\n  r: Refresh list\n  a: Add new server\n  e: Edit entry\n  t: Edit tags\n  d: Delete entry\n  p: Pin/Unpin"
+	text += renderAdvancedSettings(profile.Server)
+
+	text += "\n[::b]Applies to:[-]\n"
+	if len(profile.Matches) == 0 {
+		text += "  [#888888]no servers[-]\n"
+	}
+	for _, alias := range profile.Matches {
+		text += fmt.Sprintf("  [white]%s[-]\n", alias)
+	}
+
+	if profile.SourceFile != "" {
+		text += fmt.Sprintf("\n[::b]Defined in:[-]\n  [white]%s[-]\n", tview.Escape(fsutil.ShortenHome(profile.SourceFile)))
+	}
+
+	text += "\n[::b]Commands:[-]\n  a: Add profile\n  e: Edit profile\n  d: Delete profile\n  u/Ctrl+R: Undo/Redo\n  P/Esc: Back to servers"
+
+	sd.TextView.SetText(text)
+}
+
+// UpdateMatchBlock shows the criteria and settings of a Match block. Match blocks are read-only.
+func (sd *ServerDetails) UpdateMatchBlock(block domain.MatchBlock) {
+	text := fmt.Sprintf("[::b]Match %s[-]\n\n[::b]Criteria:[-]\n", tview.Escape(formatMatchCriteria(block.Criteria)))
+	for _, c := range block.Criteria {
+		keyword := c.Keyword
+		if c.Negated {
+			keyword = "not " + keyword
+		}
+		if c.Argument == "" {
+			text += fmt.Sprintf("  [white]%s[-]\n", keyword)
+			continue
+		}
+		text += fmt.Sprintf("  %s: [white]%s[-]\n", keyword, tview.Escape(c.Argument))
+	}
+
+	text += "\n[::b]Settings:[-]\n"
+	if len(block.Settings) == 0 {
+		text += "  [#888888]none[-]\n"
+	}
+	for _, setting := range block.Settings {
+		text += fmt.Sprintf("  [white]%s[-]\n", tview.Escape(setting))
+	}
+
+	text += fmt.Sprintf("\n[::b]Defined in:[-]\n  [white]%s:%d[-]\n", tview.Escape(fsutil.ShortenHome(block.SourceFile)), block.Line)
+	text += "\n[#888888]Match blocks are read-only in lazyssh and are kept unchanged when servers are edited.[-]\n"
+	text += "\n[::b]Commands:[-]\n  M/Esc: Back to servers"
 
 	sd.TextView.SetText(text)
 }
 
+// UpdateBackup shows a backup together with the changes restoring it would make.
+func (sd *ServerDetails) UpdateBackup(backup domain.Backup, diff string, diffErr error) {
+	text := fmt.Sprintf("[::b]Backup of %s[-]\n\n  Taken: [white]%s[-] (%s)\n  Size: [white]%s[-]\n  Before: [white]%s[-]\n  File: [white]%s[-]\n",
+		tview.Escape(fsutil.ShortenHome(backup.ConfigFile)), backup.CreatedAt.Format("2006-01-02 15:04:05"), humanizeDuration(backup.CreatedAt),
+		formatSize(backup.Size), tview.Escape(backupOperation(backup)), tview.Escape(fsutil.ShortenHome(backup.Path)))
+
+	text += "\n[::b]Changes on restore:[-]\n"
+	switch {
+	case diffErr != nil:
+		text += fmt.Sprintf("  [#FF6B6B]%s[-]\n", tview.Escape(diffErr.Error()))
+	case diff == "":
+		text += "  [#888888]identical to the current config[-]\n"
+	default:
+		text += renderDiff(diff)
+	}
+
+	text += "\n[::b]Commands:[-]\n  r: Restore this backup\n  B/Esc: Back to servers"
+
+	sd.TextView.SetText(text)
+	sd.TextView.ScrollToBeginning()
+}
+
+// renderDiff colors the lines of a unified diff.
+func renderDiff(diff string) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		color := "#BBBBBB"
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			color = "white"
+		case strings.HasPrefix(line, "@@"):
+			color = "#5FAFD7"
+		case strings.HasPrefix(line, "-"):
+			color = "#FF6B6B"
+		case strings.HasPrefix(line, "+"):
+			color = "#A0FFA0"
+		}
+		fmt.Fprintf(&sb, "[%s]%s[-]\n", color, tview.Escape(line))
+	}
+	return sb.String()
+}
+
 func (sd *ServerDetails) ShowEmpty() {
 	sd.TextView.SetText("No servers match the current filter.")
 }
+
+func (sd *ServerDetails) ShowNoMatchBlocks() {
+	sd.TextView.SetText("No Match blocks found in your SSH config.")
+}
+
+func (sd *ServerDetails) ShowNoBackups() {
+	sd.TextView.SetText("No backups yet. lazyssh backs up a config file every time it changes it.")
+}
+
+func (sd *ServerDetails) ShowNoProfiles() {
+	sd.TextView.SetText("No profiles yet. Profiles are Host blocks with wildcard patterns, such as Host *.staging.\n\nPress a to add one.")
+}
@@ -21,6 +21,8 @@ import (
 	"strings"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/services"
+	"github.com/Adembc/lazyssh/internal/fsutil"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -57,6 +59,7 @@ type ServerForm struct {
 	original      *domain.Server
 	onSave        func(domain.Server, *domain.Server)
 	onCancel      func()
+	onHelpMode    func(HelpDisplayMode)
 	app           *tview.Application // Reference to app for showing modals
 	version       string             // Version for header
 	commit        string             // Commit for header
@@ -65,6 +68,9 @@ type ServerForm struct {
 	helpMode      HelpDisplayMode    // Current help display mode
 	currentField  string             // Currently focused field
 	mainContainer *tview.Flex        // Container for form and help panel
+	configFiles   []string           // Config files a new server can be written to
+	configFile    string             // Preselected target config file
+	profile       bool               // Editing a pattern Host block instead of a server
 }
 
 func NewServerForm(mode ServerFormMode, original *domain.Server) *ServerForm {
@@ -123,6 +129,12 @@ func (sf *ServerForm) build() {
 	sf.createForwardingForm()
 	sf.createAuthenticationForm()
 	sf.createAdvancedForm()
+	// Notes are stored per server alias, so profiles don't have them
+	if !sf.profile {
+		sf.tabs = append(sf.tabs, "Notes")
+		sf.tabAbbrev["Notes"] = "Notes"
+		sf.createNotesForm()
+	}
 
 	// Setup tab bar
 	sf.updateTabBar()
@@ -172,7 +184,7 @@ func (sf *ServerForm) build() {
 	hintBar := tview.NewTextView().SetDynamicColors(true)
 	hintBar.SetBackgroundColor(tcell.Color235)
 	hintBar.SetTextAlign(tview.AlignCenter)
-	hintBar.SetText("[white]^H/^L[-] Navigate  • [white]^S[-] Save  • [white]Esc[-] Cancel")
+	hintBar.SetText("[white]^H/^L[-] Navigate  • [white]^S[-] Save  • [white]F1[-] Help  • [white]Esc[-] Cancel")
 
 	// Setup main container - header at top, hint bar at bottom
 	sf.Flex.AddItem(sf.header, 2, 0, false).
@@ -180,7 +192,7 @@ func (sf *ServerForm) build() {
 		AddItem(hintBar, 1, 0, false)
 
 	// Initialize help with first field
-	sf.updateHelp("Alias")
+	sf.updateHelp(sf.aliasField())
 
 	// Setup keyboard shortcuts
 	sf.setupKeyboardShortcuts()
@@ -196,10 +208,23 @@ func (sf *ServerForm) build() {
 }
 
 func (sf *ServerForm) titleForMode() string {
+	kind := "Server"
+	if sf.profile {
+		kind = "Profile"
+	}
 	if sf.mode == ServerFormEdit {
-		return "Edit Server"
+		return "Edit " + kind
+	}
+	return "Add " + kind
+}
+
+// aliasField returns the name of the field holding the Host patterns.
+// Servers have a single alias; profiles have a list of wildcard patterns.
+func (sf *ServerForm) aliasField() string {
+	if sf.profile {
+		return "Patterns"
 	}
-	return "Add Server"
+	return "Alias"
 }
 
 func (sf *ServerForm) getCurrentTabIndex() int {
@@ -518,14 +543,21 @@ func (sf *ServerForm) formatDetailedHelp(help *FieldHelp) string {
 	return b.String()
 }
 
-// Note: toggleHelp and rebuildLayout were removed due to hang issues
-// The help panel is now always visible on the right side
+// cycleHelpMode switches the help panel between detailed, compact and hidden.
+// The draw function of the main container picks up the new mode, so no relayout is needed here.
+func (sf *ServerForm) cycleHelpMode() {
+	sf.helpMode = sf.helpMode.Next()
+	if sf.helpMode != HelpModeOff {
+		sf.updateHelp(sf.currentField)
+	}
+	if sf.onHelpMode != nil {
+		sf.onHelpMode(sf.helpMode)
+	}
+}
 
 func (sf *ServerForm) setupKeyboardShortcuts() {
 	// Set input capture for the main flex container
 	sf.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		// Help panel is always visible - no toggle needed
-
 		// Check for Ctrl key combinations with regular keys
 		if event.Key() == tcell.KeyRune && event.Modifiers()&tcell.ModCtrl != 0 {
 			switch event.Rune() {
@@ -555,6 +587,10 @@ func (sf *ServerForm) setupKeyboardShortcuts() {
 			// ESC: Cancel
 			sf.handleCancel()
 			return nil
+		case tcell.KeyF1:
+			// F1: Cycle the help panel
+			sf.cycleHelpMode()
+			return nil
 		case tcell.KeyCtrlH:
 			// Ctrl+H: Previous tab (backup handler)
 			sf.prevTab()
@@ -906,44 +942,12 @@ func (sf *ServerForm) createAlgorithmAutocomplete(suggestions []string) func(str
 
 // validateField validates a single field and updates the validation state
 func (sf *ServerForm) validateField(fieldName, value string) string {
-	fieldValidators := GetFieldValidators()
-	validator, exists := fieldValidators[fieldName]
-	if !exists {
-		// No validator for this field, it's valid
-		sf.validation.SetError(fieldName, "")
-		return ""
+	errMsg := ""
+	if err := services.ValidateField(fieldName, value); err != nil {
+		errMsg = err.Error()
 	}
-
-	// Check required
-	if validator.Required && strings.TrimSpace(value) == "" {
-		err := fmt.Sprintf("%s is required", fieldName)
-		sf.validation.SetError(fieldName, err)
-		return err
-	}
-
-	// If field is empty and not required, it's valid
-	if value == "" {
-		sf.validation.SetError(fieldName, "")
-		return ""
-	}
-
-	// Check custom validation function
-	if validator.Validate != nil {
-		if err := validator.Validate(value); err != nil {
-			sf.validation.SetError(fieldName, err.Error())
-			return err.Error()
-		}
-	}
-
-	// Check regex pattern
-	if validator.Pattern != nil && !validator.Pattern.MatchString(value) {
-		sf.validation.SetError(fieldName, validator.Message)
-		return validator.Message
-	}
-
-	// Field is valid
-	sf.validation.SetError(fieldName, "")
-	return ""
+	sf.validation.SetError(fieldName, errMsg)
+	return errMsg
 }
 
 // addDropDownWithHelp adds a dropdown field with help support
@@ -1029,12 +1033,18 @@ func (sf *ServerForm) validateAllFields() bool {
 
 	// Validate each field based on form data
 	// Don't return early - validate all fields
-	sf.validateField("Alias", data.Alias)
-	sf.validateField("Host", data.Host)
+	sf.validateField(sf.aliasField(), data.Alias)
+	// Profiles only provide defaults, so HostName is optional and may use tokens like %h
+	if !sf.profile {
+		sf.validateField("Host", data.Host)
+	}
 	sf.validateField("Port", data.Port)
 	sf.validateField("User", data.User)
 	sf.validateField("Keys", data.Key)
 	sf.validateField("Tags", data.Tags)
+	if !sf.profile {
+		sf.validateField("Fields", data.Fields)
+	}
 
 	// Connection fields
 	sf.validateField("ConnectTimeout", data.ConnectTimeout)
@@ -1065,13 +1075,19 @@ func (sf *ServerForm) validateAllFields() bool {
 // getDefaultValues returns default form values based on mode
 func (sf *ServerForm) getDefaultValues() ServerFormData {
 	if sf.mode == ServerFormEdit && sf.original != nil {
+		port := fmt.Sprint(sf.original.Port)
+		if sf.original.Port == 0 {
+			port = "" // Profiles without a Port line
+		}
 		return ServerFormData{
 			Alias:                sf.original.Alias,
 			Host:                 sf.original.Host,
 			User:                 sf.original.User,
-			Port:                 fmt.Sprint(sf.original.Port),
+			Port:                 port,
 			Key:                  strings.Join(sf.original.IdentityFiles, ", "),
 			Tags:                 strings.Join(sf.original.Tags, ", "),
+			Notes:                sf.original.Notes,
+			Fields:               formatCustomFields(sf.original.Fields),
 			ProxyJump:            sf.original.ProxyJump,
 			ProxyCommand:         sf.original.ProxyCommand,
 			RemoteCommand:        sf.original.RemoteCommand,
@@ -1140,14 +1156,22 @@ func (sf *ServerForm) getDefaultValues() ServerFormData {
 	}
 	// For new servers, use empty values instead of SSH defaults
 	// SSH defaults will be applied by the SSH client if values are not specified
+	port := "22" // Keep port 22 as it's the standard SSH port
+	if sf.profile {
+		port = "" // A profile only sets what it is meant to share
+	}
 	return ServerFormData{
-		Alias: "",   // Explicitly empty for new servers
-		Host:  "",   // Explicitly empty for new servers
-		User:  "",   // Empty for new servers (SSH will use current username)
-		Port:  "22", // Keep port 22 as it's the standard SSH port
-		Key:   "",   // Empty for new servers (SSH will try default keys)
+		Alias: "", // Explicitly empty for new servers
+		Host:  "", // Explicitly empty for new servers
+		User:  "", // Empty for new servers (SSH will use current username)
+		Port:  port,
+		Key:   "", // Empty for new servers (SSH will try default keys)
 		Tags:  "",
 
+		// Notes and custom fields
+		Notes:  "",
+		Fields: "",
+
 		// All other fields should be empty for new servers
 		// The SSH client will use its defaults when these are not specified
 		ProxyJump:            "",
@@ -1242,8 +1266,13 @@ func (sf *ServerForm) createBasicForm() {
 	defaultValues := sf.getDefaultValues()
 
 	// Add validated input fields
-	sf.addValidatedInputField(form, "Alias:", "Alias", defaultValues.Alias, 20, GetFieldPlaceholder("Alias"))
-	sf.addValidatedInputField(form, "Host/IP:", "Host", defaultValues.Host, 20, GetFieldPlaceholder("Host"))
+	if sf.profile {
+		sf.addValidatedInputField(form, "Patterns:", "Patterns", defaultValues.Alias, 40, GetFieldPlaceholder("Patterns"))
+		sf.addInputFieldWithHelp(form, "Host/IP:", "Host", defaultValues.Host, 20, "optional, e.g. %h.staging.internal")
+	} else {
+		sf.addValidatedInputField(form, "Alias:", "Alias", defaultValues.Alias, 20, GetFieldPlaceholder("Alias"))
+		sf.addValidatedInputField(form, "Host/IP:", "Host", defaultValues.Host, 20, GetFieldPlaceholder("Host"))
+	}
 	sf.addValidatedInputField(form, "User:", "User", defaultValues.User, 20, GetFieldPlaceholder("User"))
 	sf.addValidatedInputField(form, "Port:", "Port", defaultValues.Port, 20, GetFieldPlaceholder("Port"))
 
@@ -1251,8 +1280,23 @@ func (sf *ServerForm) createBasicForm() {
 	keysField := sf.addValidatedInputField(form, "Keys:", "Keys", defaultValues.Key, 40, GetFieldPlaceholder("Keys"))
 	keysField.SetAutocompleteFunc(sf.createSSHKeyAutocomplete())
 
-	// Tags field
-	sf.addValidatedInputField(form, "Tags:", "Tags", defaultValues.Tags, 30, GetFieldPlaceholder("Tags"))
+	// Tags are stored per server alias, so profiles don't have them
+	if !sf.profile {
+		sf.addValidatedInputField(form, "Tags:", "Tags", defaultValues.Tags, 30, GetFieldPlaceholder("Tags"))
+	}
+
+	// Target file is only offered when adding and there is more than one file to choose from
+	if sf.mode == ServerFormAdd && len(sf.configFiles) > 1 {
+		options := make([]string, len(sf.configFiles))
+		selected := 0
+		for i, file := range sf.configFiles {
+			options[i] = fsutil.ShortenHome(file)
+			if file == sf.configFile {
+				selected = i
+			}
+		}
+		sf.addDropDownWithHelp(form, "Target file:", "TargetFile", options, selected)
+	}
 
 	// Add save and cancel buttons
 	form.AddButton("Save", sf.handleSaveButton)
@@ -1638,6 +1682,51 @@ func (sf *ServerForm) createAdvancedForm() {
 	sf.pages.AddPage("Advanced", form, true, false)
 }
 
+// createNotesForm creates the Notes tab: Markdown notes and custom key/value fields,
+// which are kept in lazyssh metadata rather than in the SSH config
+func (sf *ServerForm) createNotesForm() {
+	form := tview.NewForm()
+	defaultValues := sf.getDefaultValues()
+
+	sf.addTextAreaWithHelp(form, "Notes:", "Notes", defaultValues.Notes, 10, GetFieldPlaceholder("Notes"))
+	sf.addTextAreaWithHelp(form, "Fields:", "Fields", defaultValues.Fields, 6, GetFieldPlaceholder("Fields"))
+
+	// Add save and cancel buttons
+	form.AddButton("Save", sf.handleSaveButton)
+	form.AddButton("Cancel", sf.handleCancel)
+
+	// Set up form-level input capture for shortcuts
+	sf.setupFormShortcuts(form)
+
+	sf.forms["Notes"] = form
+	sf.pages.AddPage("Notes", form, true, false)
+}
+
+// addTextAreaWithHelp adds a multi-line text area with help support and real-time validation
+func (sf *ServerForm) addTextAreaWithHelp(form *tview.Form, label, fieldName, defaultValue string, height int, placeholder string) *tview.TextArea {
+	area := tview.NewTextArea().
+		SetLabel(label).
+		SetText(defaultValue, false).
+		SetSize(height, 0).
+		SetPlaceholder(placeholder)
+
+	area.SetChangedFunc(func() {
+		if err := sf.validateField(fieldName, area.GetText()); err != "" {
+			area.SetLabel(fmt.Sprintf("[red]%s[-]", label))
+		} else {
+			area.SetLabel(label)
+		}
+	})
+
+	// Add focus handler to show help
+	area.SetFocusFunc(func() {
+		sf.updateHelp(fieldName)
+	})
+
+	form.AddFormItem(area)
+	return area
+}
+
 type ServerFormData struct {
 	Alias string
 	Host  string
@@ -1646,6 +1735,13 @@ type ServerFormData struct {
 	Key   string
 	Tags  string
 
+	// Notes tab (stored in lazyssh metadata)
+	Notes  string
+	Fields string
+
+	// Target config file (add mode only)
+	ConfigFile string
+
 	// Connection and proxy settings
 	ProxyJump            string
 	ProxyCommand         string
@@ -1754,6 +1850,20 @@ func (sf *ServerForm) getFormData() ServerFormData {
 		return ""
 	}
 
+	// Helper function to get text from TextArea across all forms
+	getTextAreaText := func(fieldName string) string {
+		for _, form := range sf.forms {
+			for i := 0; i < form.GetFormItemCount(); i++ {
+				if area, ok := form.GetFormItem(i).(*tview.TextArea); ok {
+					if strings.HasPrefix(stripColorTags(strings.TrimSpace(area.GetLabel())), fieldName) {
+						return strings.TrimSpace(area.GetText())
+					}
+				}
+			}
+		}
+		return ""
+	}
+
 	// Helper function to get selected option from DropDown across all forms
 	getDropdownValue := func(fieldName string) string {
 		for _, form := range sf.forms {
@@ -1774,12 +1884,17 @@ func (sf *ServerForm) getFormData() ServerFormData {
 	}
 
 	return ServerFormData{
-		Alias: getFieldText("Alias:"),
+		Alias: getFieldText(sf.aliasField() + ":"),
 		Host:  getFieldText("Host/IP:"),
 		User:  getFieldText("User:"),
 		Port:  getFieldText("Port:"),
 		Key:   getFieldText("Keys:"),
 		Tags:  getFieldText("Tags:"),
+		// Notes tab
+		Notes:  getTextAreaText("Notes:"),
+		Fields: getTextAreaText("Fields:"),
+		// Target config file
+		ConfigFile: sf.getSelectedConfigFile(),
 		// Connection and proxy settings
 		ProxyJump:            getFieldText("ProxyJump:"),
 		ProxyCommand:         getFieldText("ProxyCommand:"),
@@ -1848,6 +1963,25 @@ func (sf *ServerForm) getFormData() ServerFormData {
 	}
 }
 
+// getSelectedConfigFile returns the config file chosen in the "Target file" dropdown.
+// It falls back to the preselected file when the dropdown is not shown.
+func (sf *ServerForm) getSelectedConfigFile() string {
+	form, ok := sf.forms["Basic"]
+	if !ok {
+		return sf.configFile
+	}
+	for i := 0; i < form.GetFormItemCount(); i++ {
+		dropdown, ok := form.GetFormItem(i).(*tview.DropDown)
+		if !ok || stripColorTags(strings.TrimSpace(dropdown.GetLabel())) != "Target file:" {
+			continue
+		}
+		if idx, _ := dropdown.GetCurrentOption(); idx >= 0 && idx < len(sf.configFiles) {
+			return sf.configFiles[idx]
+		}
+	}
+	return sf.configFile
+}
+
 // parseSessionType converts dropdown display value to actual value
 func (sf *ServerForm) parseSessionType(value string) string {
 	// First handle the default value format
@@ -2041,10 +2175,12 @@ func (sf *ServerForm) serversDiffer(a, b domain.Server) bool {
 
 	// Fields to skip during comparison (lazyssh metadata fields)
 	skipFields := map[string]bool{
-		"Aliases":  true, // Computed field
-		"LastSeen": true, // Metadata field
-		"PinnedAt": true, // Metadata field
-		"SSHCount": true, // Metadata field
+		"Aliases":    true, // Computed field
+		"LastSeen":   true, // Metadata field
+		"PinnedAt":   true, // Metadata field
+		"SSHCount":   true, // Metadata field
+		"SourceFile": true, // Computed field
+		"Effective":  true, // Computed field
 	}
 
 	// Iterate through all fields
@@ -2141,6 +2277,9 @@ func (sf *ServerForm) slicesEqual(a, b reflect.Value) bool {
 
 func (sf *ServerForm) dataToServer(data ServerFormData) domain.Server {
 	port := 22
+	if sf.profile {
+		port = 0 // Leave the port to the servers unless the profile sets one
+	}
 	if data.Port != "" {
 		if n, err := strconv.Atoi(data.Port); err == nil && n > 0 {
 			port = n
@@ -2157,6 +2296,9 @@ func (sf *ServerForm) dataToServer(data ServerFormData) domain.Server {
 		}
 	}
 
+	// Invalid fields are rejected by validateAllFields before the form is saved
+	fields, _ := services.ParseCustomFields(data.Fields)
+
 	var keys []string
 	if data.Key != "" {
 		parts := strings.Split(data.Key, ",")
@@ -2188,6 +2330,9 @@ func (sf *ServerForm) dataToServer(data ServerFormData) domain.Server {
 		Port:                 port,
 		IdentityFiles:        keys,
 		Tags:                 tags,
+		Notes:                data.Notes,
+		Fields:               fields,
+		SourceFile:           data.ConfigFile,
 		ProxyJump:            data.ProxyJump,
 		ProxyCommand:         data.ProxyCommand,
 		RemoteCommand:        data.RemoteCommand,
@@ -2251,8 +2396,9 @@ func (sf *ServerForm) dataToServer(data ServerFormData) domain.Server {
 		server.PinnedAt = sf.original.PinnedAt
 		server.LastSeen = sf.original.LastSeen
 		server.SSHCount = sf.original.SSHCount
-		// Also preserve Aliases (computed field)
+		// Also preserve computed fields
 		server.Aliases = sf.original.Aliases
+		server.SourceFile = sf.original.SourceFile
 	}
 
 	return server
@@ -2268,11 +2414,45 @@ func (sf *ServerForm) OnCancel(fn func()) *ServerForm {
 	return sf
 }
 
+// SetHelpMode sets how the help panel starts out.
+func (sf *ServerForm) SetHelpMode(mode HelpDisplayMode) *ServerForm {
+	sf.helpMode = mode
+	return sf
+}
+
+// OnHelpModeChange sets what happens when F1 switches the help panel to another mode.
+func (sf *ServerForm) OnHelpModeChange(fn func(HelpDisplayMode)) *ServerForm {
+	sf.onHelpMode = fn
+	return sf
+}
+
 func (sf *ServerForm) SetApp(app *tview.Application) *ServerForm {
 	sf.app = app
 	return sf
 }
 
+// SetConfigFiles sets the config files offered as targets for a new server and the one selected by default.
+// It must be called before SetVersionInfo, which builds the form.
+func (sf *ServerForm) SetConfigFiles(files []string, selected string) *ServerForm {
+	sf.configFiles = files
+	sf.configFile = ""
+	for _, file := range files {
+		if file == selected {
+			sf.configFile = selected
+			break
+		}
+	}
+	return sf
+}
+
+// AsProfile switches the form to editing a pattern Host block: the alias becomes a
+// pattern list, HostName is optional and tags are hidden.
+// It must be called before SetVersionInfo, which builds the form.
+func (sf *ServerForm) AsProfile() *ServerForm {
+	sf.profile = true
+	return sf
+}
+
 func (sf *ServerForm) SetVersionInfo(version, commit string) *ServerForm {
 	sf.version = version
 	sf.commit = commit
@@ -15,6 +15,8 @@
 package ui
 
 import (
+	"sort"
+
 	"github.com/Adembc/lazyssh/internal/core/domain"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -23,13 +25,18 @@ import (
 type ServerList struct {
 	*tview.List
 	servers           []domain.Server
+	results           []domain.SearchResult
+	marked            map[string]bool // aliases marked for bulk actions, kept across updates
+	anchor            string          // the alias last marked or unmarked, where a range starts
+	findings          map[string][]domain.Finding
 	onSelection       func(domain.Server)
 	onSelectionChange func(domain.Server)
 }
 
 func NewServerList() *ServerList {
 	list := &ServerList{
-		List: tview.NewList(),
+		List:   tview.NewList(),
+		marked: make(map[string]bool),
 	}
 	list.build()
 	return list
@@ -54,12 +61,15 @@ func (sl *ServerList) build() {
 	})
 }
 
-func (sl *ServerList) UpdateServers(servers []domain.Server) {
-	sl.servers = servers
+// UpdateResults lists search results in their order, highlighting the characters that matched.
+func (sl *ServerList) UpdateResults(results []domain.SearchResult) {
+	sl.servers = make([]domain.Server, len(results))
+	sl.results = results
 	sl.List.Clear()
 
-	for i := range servers {
-		primary, secondary := formatServerLine(servers[i])
+	for i := range results {
+		sl.servers[i] = results[i].Server
+		primary, secondary := sl.formatItem(i)
 		idx := i
 		sl.List.AddItem(primary, secondary, 0, func() {
 			if sl.onSelection != nil {
@@ -84,6 +94,116 @@ func (sl *ServerList) GetSelectedServer() (domain.Server, bool) {
 	return domain.Server{}, false
 }
 
+// SelectAlias selects the server with the given alias, reporting whether it is listed.
+func (sl *ServerList) SelectAlias(alias string) bool {
+	for i := range sl.servers {
+		if sl.servers[i].Alias == alias {
+			sl.List.SetCurrentItem(i)
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleMark marks or unmarks the selected server.
+func (sl *ServerList) ToggleMark() {
+	server, ok := sl.GetSelectedServer()
+	if !ok {
+		return
+	}
+	if sl.marked[server.Alias] {
+		delete(sl.marked, server.Alias)
+	} else {
+		sl.marked[server.Alias] = true
+	}
+	sl.anchor = server.Alias
+	sl.refreshItems()
+}
+
+// MarkRange marks every server from the one last marked or unmarked to the selected one.
+func (sl *ServerList) MarkRange() {
+	current := sl.List.GetCurrentItem()
+	if current < 0 || current >= len(sl.servers) {
+		return
+	}
+	start := current
+	for i := range sl.servers {
+		if sl.servers[i].Alias == sl.anchor {
+			start = i
+			break
+		}
+	}
+	if start > current {
+		start, current = current, start
+	}
+	for i := start; i <= current; i++ {
+		sl.marked[sl.servers[i].Alias] = true
+	}
+	sl.refreshItems()
+}
+
+// MarkAll marks every listed server, or unmarks them all if they already are.
+func (sl *ServerList) MarkAll() {
+	all := true
+	for i := range sl.servers {
+		all = all && sl.marked[sl.servers[i].Alias]
+	}
+	for i := range sl.servers {
+		if all {
+			delete(sl.marked, sl.servers[i].Alias)
+		} else {
+			sl.marked[sl.servers[i].Alias] = true
+		}
+	}
+	sl.refreshItems()
+}
+
+// ClearMarks unmarks every server.
+func (sl *ServerList) ClearMarks() {
+	sl.marked = make(map[string]bool)
+	sl.anchor = ""
+	sl.refreshItems()
+}
+
+// MarkedAliases returns the aliases of the marked servers, sorted. Servers stay marked while
+// a search hides them.
+func (sl *ServerList) MarkedAliases() []string {
+	aliases := make([]string, 0, len(sl.marked))
+	for alias := range sl.marked {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// formatItem renders a listed server, with a check mark column while any server is marked.
+func (sl *ServerList) formatItem(i int) (primary, secondary string) {
+	primary, secondary = formatServerLine(sl.results[i])
+	if badge := lintBadge(sl.findings[sl.servers[i].Alias]); badge != "" {
+		primary += " " + badge
+	}
+	if len(sl.marked) == 0 {
+		return primary, secondary
+	}
+	if sl.marked[sl.servers[i].Alias] {
+		return "[#FFD75F::b]✓[-:-:-] " + primary, secondary
+	}
+	return "  " + primary, secondary
+}
+
+// SetFindings sets the lint findings by alias and updates the badges of the listed servers.
+func (sl *ServerList) SetFindings(findings map[string][]domain.Finding) {
+	sl.findings = findings
+	sl.refreshItems()
+}
+
+func (sl *ServerList) refreshItems() {
+	for i := range sl.results {
+		primary, secondary := sl.formatItem(i)
+		sl.List.SetItemText(i, primary, secondary)
+	}
+}
+
 func (sl *ServerList) OnSelection(fn func(server domain.Server)) *ServerList {
 	sl.onSelection = fn
 	return sl
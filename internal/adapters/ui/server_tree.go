@@ -0,0 +1,290 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// TreeGrouping selects how servers are grouped in the tree view, or the flat list.
+type TreeGrouping int
+
+const (
+	GroupByNone TreeGrouping = iota
+	GroupByTag
+	GroupByDomain
+	GroupByProxyJump
+)
+
+func (g TreeGrouping) String() string {
+	switch g {
+	case GroupByTag:
+		return "tag"
+	case GroupByDomain:
+		return "domain"
+	case GroupByProxyJump:
+		return "ProxyJump"
+	default:
+		return "none"
+	}
+}
+
+// Next cycles from the flat list through the tree groupings and back.
+func (g TreeGrouping) Next() TreeGrouping {
+	if g >= GroupByProxyJump {
+		return GroupByNone
+	}
+	return g + 1
+}
+
+// Layout encodes the grouping for saved UI state: "list" for the flat list, otherwise
+// "tree:" and the grouping, e.g. "tree:domain". ParseLayout decodes it.
+func (g TreeGrouping) Layout() string {
+	if g == GroupByNone {
+		return "list"
+	}
+	return "tree:" + strings.ToLower(g.String())
+}
+
+// ParseLayout decodes a layout encoded by Layout.
+func ParseLayout(s string) (TreeGrouping, bool) {
+	for g := GroupByNone; ; g++ {
+		if g.Layout() == s {
+			return g, true
+		}
+		if g == GroupByProxyJump {
+			return GroupByNone, false
+		}
+	}
+}
+
+// groupKey returns the tree group server belongs to. Keys in parentheses collect the servers
+// the grouping doesn't apply to; they are listed after the others.
+func (g TreeGrouping) groupKey(server domain.Server) string {
+	switch g {
+	case GroupByTag:
+		if len(server.Tags) > 0 {
+			return server.Tags[0]
+		}
+		return "(untagged)"
+	case GroupByDomain:
+		return domainSuffix(server)
+	case GroupByProxyJump:
+		if jump := strings.TrimSpace(server.ProxyJump); jump != "" && !strings.EqualFold(jump, "none") {
+			return jump
+		}
+		return "(direct)"
+	default:
+		return ""
+	}
+}
+
+// domainSuffix returns the domain of the server's HostName (or alias), without its first label.
+func domainSuffix(server domain.Server) string {
+	host := strings.ToLower(strings.TrimSuffix(server.Host, "."))
+	if host == "" {
+		host = strings.ToLower(server.Alias)
+	}
+	if net.ParseIP(host) != nil {
+		return "(IP address)"
+	}
+	if _, suffix, ok := strings.Cut(host, "."); ok && suffix != "" {
+		return suffix
+	}
+	return "(no domain)"
+}
+
+// ServerTree lists servers grouped into collapsible nodes, as an alternative to ServerList.
+type ServerTree struct {
+	*tview.TreeView
+	grouping          TreeGrouping
+	collapsed         map[string]bool // groups the user collapsed, kept across updates
+	findings          map[string][]domain.Finding
+	labels            map[*tview.TreeNode]string // server lines without their lint badge
+	onSelectionChange func(domain.Server)
+}
+
+func NewServerTree() *ServerTree {
+	tree := &ServerTree{
+		TreeView:  tview.NewTreeView(),
+		grouping:  GroupByTag,
+		collapsed: make(map[string]bool),
+	}
+	tree.build()
+	return tree
+}
+
+func (st *ServerTree) build() {
+	st.TreeView.SetRoot(tview.NewTreeNode("")).
+		SetTopLevel(1).
+		SetGraphicsColor(tcell.Color240)
+	st.TreeView.SetBorder(true).
+		SetTitle(" Servers ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	st.TreeView.SetChangedFunc(func(node *tview.TreeNode) {
+		if server, ok := node.GetReference().(domain.Server); ok && st.onSelectionChange != nil {
+			st.onSelectionChange(server)
+		}
+	})
+}
+
+// SetGrouping changes how servers are grouped from the next UpdateResults on.
+func (st *ServerTree) SetGrouping(grouping TreeGrouping) {
+	st.grouping = grouping
+	st.collapsed = make(map[string]bool)
+}
+
+// SetFindings sets the lint findings by alias and updates the badges of the listed servers.
+func (st *ServerTree) SetFindings(findings map[string][]domain.Finding) {
+	st.findings = findings
+	for node, label := range st.labels {
+		st.labelServer(node, label)
+	}
+}
+
+// labelServer sets the text of a server node to label with the server's lint badge.
+func (st *ServerTree) labelServer(node *tview.TreeNode, label string) {
+	server := node.GetReference().(domain.Server)
+	if badge := lintBadge(st.findings[server.Alias]); badge != "" {
+		label += " " + badge
+	}
+	node.SetText(label)
+}
+
+// UpdateResults groups the results, keeping their order within each group, and selects the
+// first server.
+func (st *ServerTree) UpdateResults(results []domain.SearchResult) {
+	groups := make(map[string][]domain.SearchResult)
+	keys := make([]string, 0)
+	for _, result := range results {
+		key := st.grouping.groupKey(result.Server)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], result)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		pi, pj := strings.HasPrefix(keys[i], "("), strings.HasPrefix(keys[j], "(")
+		if pi != pj {
+			return pj
+		}
+		return strings.ToLower(keys[i]) < strings.ToLower(keys[j])
+	})
+
+	root := st.TreeView.GetRoot().ClearChildren()
+	st.labels = make(map[*tview.TreeNode]string, len(results))
+	var first *tview.TreeNode
+	for _, key := range keys {
+		group := tview.NewTreeNode("").
+			SetReference(key).
+			SetExpanded(!st.collapsed[key])
+		for _, result := range groups[key] {
+			primary, _ := formatServerLine(result)
+			node := tview.NewTreeNode("").SetReference(result.Server)
+			st.labels[node] = primary
+			st.labelServer(node, primary)
+			group.AddChild(node)
+			if first == nil && group.IsExpanded() {
+				first = node
+			}
+		}
+		st.labelGroup(group)
+		root.AddChild(group)
+	}
+
+	switch {
+	case first != nil:
+		st.TreeView.SetCurrentNode(first)
+		if st.onSelectionChange != nil {
+			st.onSelectionChange(first.GetReference().(domain.Server))
+		}
+	case len(root.GetChildren()) > 0:
+		st.TreeView.SetCurrentNode(root.GetChildren()[0])
+	default:
+		st.TreeView.SetCurrentNode(nil)
+	}
+}
+
+// ToggleSelectedGroup expands or collapses the selected group, reporting whether a group was selected.
+func (st *ServerTree) ToggleSelectedGroup() bool {
+	node := st.TreeView.GetCurrentNode()
+	if node == nil {
+		return false
+	}
+	if _, ok := node.GetReference().(string); !ok {
+		return false
+	}
+	st.ToggleGroup(node)
+	return true
+}
+
+func (st *ServerTree) GetSelectedServer() (domain.Server, bool) {
+	node := st.TreeView.GetCurrentNode()
+	if node == nil {
+		return domain.Server{}, false
+	}
+	server, ok := node.GetReference().(domain.Server)
+	return server, ok
+}
+
+// SelectAlias selects the server with the given alias, expanding its group, and reports whether it is listed.
+func (st *ServerTree) SelectAlias(alias string) bool {
+	for _, group := range st.TreeView.GetRoot().GetChildren() {
+		for _, node := range group.GetChildren() {
+			if server, ok := node.GetReference().(domain.Server); ok && server.Alias == alias {
+				if !group.IsExpanded() {
+					st.ToggleGroup(group)
+				}
+				st.TreeView.SetCurrentNode(node)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ToggleGroup expands or collapses a group node.
+func (st *ServerTree) ToggleGroup(group *tview.TreeNode) {
+	key, ok := group.GetReference().(string)
+	if !ok {
+		return
+	}
+	group.SetExpanded(!group.IsExpanded())
+	st.collapsed[key] = !group.IsExpanded()
+	st.labelGroup(group)
+}
+
+func (st *ServerTree) OnSelectionChange(fn func(server domain.Server)) *ServerTree {
+	st.onSelectionChange = fn
+	return st
+}
+
+func (st *ServerTree) labelGroup(group *tview.TreeNode) {
+	arrow := "▾"
+	if !group.IsExpanded() {
+		arrow = "▸"
+	}
+	group.SetText(fmt.Sprintf("[::b]%s %s[-:-:-] [#888888](%d)[-]", arrow, tview.Escape(group.GetReference().(string)), len(group.GetChildren())))
+}
@@ -0,0 +1,75 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestServerTreeGrouping(t *testing.T) {
+	servers := []domain.Server{
+		{Alias: "web1", Host: "web1.prod.example.com", Tags: []string{"prod", "web"}, ProxyJump: "bastion"},
+		{Alias: "web2", Host: "web2.prod.example.com", Tags: []string{"prod"}, ProxyJump: "bastion"},
+		{Alias: "db", Host: "10.0.0.5", ProxyJump: "none"},
+		{Alias: "nas"},
+	}
+	results := make([]domain.SearchResult, len(servers))
+	for i := range servers {
+		results[i] = domain.SearchResult{Server: servers[i]}
+	}
+
+	tests := []struct {
+		grouping TreeGrouping
+		want     string
+	}{
+		{GroupByTag, "prod: web1 web2; (untagged): db nas"},
+		{GroupByDomain, "prod.example.com: web1 web2; (IP address): db; (no domain): nas"},
+		{GroupByProxyJump, "bastion: web1 web2; (direct): db nas"},
+	}
+	for _, tt := range tests {
+		tree := NewServerTree()
+		tree.SetGrouping(tt.grouping)
+		tree.UpdateResults(results)
+
+		groups := make([]string, 0)
+		for _, group := range tree.GetRoot().GetChildren() {
+			aliases := make([]string, 0)
+			for _, node := range group.GetChildren() {
+				aliases = append(aliases, node.GetReference().(domain.Server).Alias)
+			}
+			groups = append(groups, group.GetReference().(string)+": "+strings.Join(aliases, " "))
+		}
+		if got := strings.Join(groups, "; "); got != tt.want {
+			t.Errorf("grouping by %s = %q, want %q", tt.grouping, got, tt.want)
+		}
+		if server, ok := tree.GetSelectedServer(); !ok || server.Alias != "web1" {
+			t.Errorf("grouping by %s selected %q, want web1", tt.grouping, server.Alias)
+		}
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	for g := GroupByNone; g <= GroupByProxyJump; g++ {
+		if got, ok := ParseLayout(g.Layout()); !ok || got != g {
+			t.Errorf("ParseLayout(%q) = %v, %v, want %v", g.Layout(), got, ok, g)
+		}
+	}
+	if _, ok := ParseLayout("tree:color"); ok {
+		t.Errorf("ParseLayout() accepted an unknown grouping")
+	}
+}
@@ -15,15 +15,22 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/gdamore/tcell/v2"
 	"go.uber.org/zap"
 
+	"github.com/Adembc/lazyssh/internal/core/domain"
 	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/Adembc/lazyssh/internal/core/services"
 	"github.com/rivo/tview"
 )
 
 type App interface {
 	Run() error
+	// Fresh starts with the default layout instead of restoring the previous session.
+	Fresh(fresh bool) App
 }
 
 type tui struct {
@@ -34,32 +41,71 @@ type tui struct {
 
 	app           *tview.Application
 	serverService ports.ServerService
+	state         ports.StateRepository
+	fresh         bool // ignore the saved UI state on start
 
-	header     *AppHeader
-	searchBar  *SearchBar
-	hintBar    *tview.TextView
-	serverList *ServerList
-	details    *ServerDetails
-	statusBar  *tview.TextView
+	header         *AppHeader
+	searchBar      *SearchBar
+	groupTree      *GroupTree
+	hintBar        *tview.TextView
+	serverList     *ServerList
+	serverTree     *ServerTree
+	profileList    *ProfileList
+	matchBlockList *MatchBlockList
+	backupList     *BackupList
+	details        *ServerDetails
+	statusBar      *tview.TextView
 
 	root    *tview.Flex
 	left    *tview.Flex
 	content *tview.Flex
 
-	sortMode      SortMode
+	sortMode      services.SortMode
+	helpMode      HelpDisplayMode // how forms show field help
+	grouping      TreeGrouping    // GroupByNone shows the flat server list
 	searchVisible bool
+	view          listView
+
+	// form is the open add/edit form, and formVersion the config version when it was opened.
+	form        *ServerForm
+	formVersion string
+
+	// lintVersion is the config version the last lint run checked.
+	lintVersion string
+
+	// dismissedOrphans are the orphaned metadata entries the user chose to keep this session.
+	dismissedOrphans map[string]bool
 }
 
-func NewTUI(logger *zap.SugaredLogger, ss ports.ServerService, version, commit string) App {
+// listView selects what the left pane lists.
+type listView int
+
+const (
+	viewServers listView = iota
+	viewProfiles
+	viewMatchBlocks
+	viewBackups
+)
+
+func NewTUI(logger *zap.SugaredLogger, ss ports.ServerService, sr ports.StateRepository, version, commit string) App {
 	return &tui{
 		logger:        logger,
 		app:           tview.NewApplication(),
 		serverService: ss,
+		state:         sr,
 		version:       version,
 		commit:        commit,
+		helpMode:      HelpModeNormal,
+
+		dismissedOrphans: make(map[string]bool),
 	}
 }
 
+func (t *tui) Fresh(fresh bool) App {
+	t.fresh = fresh
+	return t
+}
+
 func (t *tui) Run() error {
 	defer func() {
 		if r := recover(); r != nil {
@@ -69,8 +115,19 @@ func (t *tui) Run() error {
 	t.app.EnableMouse(true)
 	t.initializeTheme().buildComponents().buildLayout().bindEvents().loadInitialData()
 	t.app.SetRoot(t.root, true)
+	if t.searchVisible { // a restored search: start in the results, not the search bar
+		t.app.SetFocus(t.serverPane())
+	}
+	t.checkOrphanedMetadata()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go t.watchExternalChanges(t.serverService.Watch(stop))
+
 	t.logger.Infow("starting TUI application", "version", t.version, "commit", t.commit)
-	if err := t.app.Run(); err != nil {
+	err := t.app.Run()
+	t.saveState()
+	if err != nil {
 		t.logger.Errorw("application run error", "error", err)
 		return err
 	}
@@ -93,23 +150,32 @@ func (t *tui) buildComponents() *tui {
 	t.header = NewAppHeader(t.version, t.commit, RepoURL)
 	t.searchBar = NewSearchBar().
 		OnSearch(t.handleSearchInput).
-		OnEscape(t.hideSearchBar)
+		OnEscape(t.hideSearchBar).
+		OnSave(t.handleSearchSave)
+	t.groupTree = NewGroupTree().
+		OnSelect(t.handleGroupSelect).
+		OnResize(t.handleGroupResize)
 	t.hintBar = NewHintBar()
 	t.serverList = NewServerList().
 		OnSelectionChange(t.handleServerSelectionChange)
-	t.details = NewServerDetails()
+	t.serverTree = NewServerTree().
+		OnSelectionChange(t.handleServerSelectionChange)
+	t.profileList = NewProfileList().
+		OnSelectionChange(t.handleProfileSelectionChange)
+	t.matchBlockList = NewMatchBlockList().
+		OnSelectionChange(t.handleMatchBlockSelectionChange)
+	t.backupList = NewBackupList().
+		OnSelectionChange(t.handleBackupSelectionChange)
+	t.details = NewServerDetails().
+		OnResolveEffective(t.resolveEffective)
 	t.statusBar = NewStatusBar()
 
-	// default sort mode
-	t.sortMode = SortByAliasAsc
-
 	return t
 }
 
 func (t *tui) buildLayout() *tui {
-	t.left = tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(t.hintBar, 1, 0, false).
-		AddItem(t.serverList, 0, 1, true)
+	t.left = tview.NewFlex().SetDirection(tview.FlexRow)
+	t.layoutServerPane()
 
 	right := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(t.details, 0, 1, false)
@@ -130,17 +196,77 @@ func (t *tui) bindEvents() *tui {
 	return t
 }
 
+// loadState returns the UI state saved by the previous session, or the zero state with --fresh.
+func (t *tui) loadState() domain.UIState {
+	if t.fresh {
+		return domain.UIState{}
+	}
+	state, err := t.state.LoadState()
+	if err != nil {
+		t.logger.Warnw("failed to load UI state", "error", err)
+	}
+	return state
+}
+
+// restoreState applies the sort mode, layout, help mode and search of a saved state.
+// Values that no longer parse are ignored, leaving the defaults.
+func (t *tui) restoreState(state domain.UIState) {
+	if mode, ok := services.ParseSortMode(state.SortMode); ok {
+		t.sortMode = mode
+	}
+	if mode, ok := ParseHelpDisplayMode(state.HelpMode); ok {
+		t.helpMode = mode
+	}
+	if grouping, ok := ParseLayout(state.Layout); ok {
+		t.grouping = grouping
+		t.serverTree.SetGrouping(grouping)
+	}
+	if state.Query != "" {
+		t.searchVisible = true
+		t.searchBar.SetText(state.Query)
+	}
+	t.layoutServerPane()
+}
+
+// saveState remembers the UI state for the next session.
+func (t *tui) saveState() {
+	state := domain.UIState{
+		SortMode: t.sortMode.Key(),
+		HelpMode: t.helpMode.String(),
+		Layout:   t.grouping.Layout(),
+		Query:    strings.TrimSpace(t.searchBar.GetText()),
+	}
+	if server, ok := t.selectedServer(); ok {
+		state.SelectedAlias = server.Alias
+	}
+	if err := t.state.SaveState(state); err != nil {
+		t.logger.Warnw("failed to save UI state", "error", err)
+	}
+}
+
 func (t *tui) loadInitialData() *tui {
-	servers, _ := t.serverService.ListServers("")
-	sortServersForUI(servers, t.sortMode)
+	state := t.loadState()
+	t.restoreState(state)
 	t.updateListTitle()
-	t.serverList.UpdateServers(servers)
+	t.refreshServerList()
+	if state.SelectedAlias != "" && t.selectServer(state.SelectedAlias) {
+		if server, ok := t.selectedServer(); ok {
+			t.details.UpdateServer(server)
+		}
+	}
 
 	return t
 }
 
 func (t *tui) updateListTitle() {
 	if t.serverList != nil {
-		t.serverList.SetTitle(" Servers — Sort: " + t.sortMode.String() + " ")
+		title := " Servers — Sort: " + t.sortMode.String() + " "
+		if n := len(t.serverList.MarkedAliases()); n > 0 {
+			title += fmt.Sprintf("— %d marked ", n)
+		}
+		t.serverList.SetTitle(title)
+	}
+	if t.serverTree != nil {
+		t.serverTree.SetTitle(" Servers by " + t.grouping.String() + " — Sort: " + t.sortMode.String() + " ")
 	}
 }
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -78,14 +79,39 @@ func pinnedIcon(pinnedAt time.Time) string {
 	return "📌" // pinned
 }
 
-func formatServerLine(s domain.Server) (primary, secondary string) {
+func formatServerLine(r domain.SearchResult) (primary, secondary string) {
+	s := r.Server
 	icon := cellPad(pinnedIcon(s.PinnedAt), 2)
 	// Use a consistent color for alias; the icon reflects pinning
-	primary = fmt.Sprintf("%s [white::b]%-12s[-] [#AAAAAA]%-18s[-] [#888888]Last SSH: %s[-]  %s", icon, s.Alias, s.Host, humanizeDuration(s.LastSeen), renderTagBadgesForList(s.Tags))
+	alias := highlightMatches(fmt.Sprintf("%-12s", s.Alias), r.AliasMatches, "white")
+	host := highlightMatches(fmt.Sprintf("%-18s", s.Host), r.HostMatches, "#AAAAAA")
+	primary = fmt.Sprintf("%s [white::b]%s[-] [#AAAAAA]%s[-] [#888888]Last SSH: %s[-]  %s", icon, alias, host, humanizeDuration(s.LastSeen), renderTagBadgesForList(s.Tags))
 	secondary = ""
 	return
 }
 
+// highlightMatches colors the runes of text at the given offsets, switching back to color after each one.
+func highlightMatches(text string, positions []int, color string) string {
+	if len(positions) == 0 {
+		return text
+	}
+	matched := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		matched[pos] = true
+	}
+	var b strings.Builder
+	i := 0
+	for _, r := range text {
+		if matched[i] {
+			b.WriteString("[#FFD75F]" + string(r) + "[" + color + "]")
+		} else {
+			b.WriteRune(r)
+		}
+		i++
+	}
+	return b.String()
+}
+
 func humanizeDuration(t time.Time) string {
 	if t.IsZero() {
 		return "never"
@@ -120,6 +146,25 @@ func humanizeDuration(t time.Time) string {
 	return fmt.Sprintf("%dy ago", years)
 }
 
+// formatCustomFields writes custom fields one "key: value" per line, sorted by key.
+func formatCustomFields(fields map[string]string) string {
+	keys := sortedFieldKeys(fields)
+	lines := make([]string, len(keys))
+	for i, key := range keys {
+		lines[i] = key + ": " + fields[key]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func sortedFieldKeys(fields map[string]string) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // BuildSSHCommand constructs a ready-to-run ssh command for the given server.
 // Format: ssh [options] [user@]host [command]
 func BuildSSHCommand(s domain.Server) string {
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/services"
 )
 
 func TestBuildSSHCommand_PortForwarding(t *testing.T) {
@@ -171,3 +172,39 @@ func TestBuildSSHCommand_CompleteCommand(t *testing.T) {
 		t.Errorf("Command should contain 'admin@example.com', got: %q", result)
 	}
 }
+
+func TestParseCustomFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "empty", text: "  \n", want: nil},
+		{name: "fields", text: "owner: alice\n\nticket: https://jira/OPS-1\n", want: map[string]string{"owner": "alice", "ticket": "https://jira/OPS-1"}},
+		{name: "empty value", text: "rack:", want: map[string]string{"rack": ""}},
+		{name: "missing colon", text: "owner alice", wantErr: true},
+		{name: "missing key", text: ": alice", wantErr: true},
+		{name: "duplicate key", text: "owner: alice\nowner: bob", wantErr: true},
+		{name: "key with equals sign", text: "a=b: c", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := services.ParseCustomFields(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("services.ParseCustomFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if formatCustomFields(got) != formatCustomFields(tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("services.ParseCustomFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	fields := map[string]string{"rack": "B12", "owner": "alice"}
+	if got := formatCustomFields(fields); got != "owner: alice\nrack: B12" {
+		t.Errorf("formatCustomFields() = %q", got)
+	}
+}
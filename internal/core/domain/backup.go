@@ -0,0 +1,27 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// Backup is a copy of an SSH config file that lazyssh saved before changing the file.
+type Backup struct {
+	Path       string
+	ConfigFile string // the config file this is a backup of
+	CreatedAt  time.Time
+	Size       int64
+	Operation  string // the change that triggered the backup, empty if unknown
+	Original   bool   // the one-time snapshot taken before lazyssh's first change
+}
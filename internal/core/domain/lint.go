@@ -0,0 +1,39 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// Severity ranks how serious a lint finding is.
+type Severity int
+
+const (
+	SeverityWarning Severity = iota // works, but likely not as intended
+	SeverityError                   // ssh fails or ignores the setting
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Finding is a problem found in the SSH config of a server.
+type Finding struct {
+	Severity Severity
+	Alias    string
+	Message  string
+	File     string
+	Line     int
+}
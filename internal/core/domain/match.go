@@ -0,0 +1,31 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// MatchBlock is a read-only view of a Match block in the SSH config.
+type MatchBlock struct {
+	Criteria   []MatchCriterion
+	Settings   []string // setting lines as written, e.g. "User deploy"
+	SourceFile string
+	Line       int
+}
+
+// MatchCriterion is a single condition of a Match line, e.g. host "*.prod" or exec "test -f ~/.vpn".
+// Argument is empty for criteria without one (all, canonical, final).
+type MatchCriterion struct {
+	Keyword  string
+	Argument string
+	Negated  bool
+}
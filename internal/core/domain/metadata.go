@@ -0,0 +1,27 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// MetadataOrphan is a metadata entry whose alias no longer exists in the SSH config,
+// typically because the Host was renamed outside lazyssh. MatchedAlias is the server
+// with the same HostName, User and Port that the entry most likely belongs to now,
+// or empty if there is no single such server.
+type MetadataOrphan struct {
+	Alias        string
+	MatchedAlias string
+	Tags         []string
+	SSHCount     int
+	Pinned       bool
+}
@@ -0,0 +1,31 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// SearchResult is a server matching a search query. Score ranks it against the other
+// results (higher is better); AliasMatches and HostMatches are the rune offsets in
+// Server.Alias and Server.Host that matched, so they can be highlighted.
+type SearchResult struct {
+	Server       Server
+	Score        int
+	AliasMatches []int
+	HostMatches  []int
+}
+
+// SavedSearch is a search query saved under a name, shown as a group of servers.
+type SavedSearch struct {
+	Name  string
+	Query string
+}
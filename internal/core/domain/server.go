@@ -16,6 +16,13 @@ package domain
 
 import "time"
 
+// PingResult is the outcome of checking whether a server's SSH port accepts connections.
+type PingResult struct {
+	Up      bool
+	Latency time.Duration
+	At      time.Time
+}
+
 type Server struct {
 	Alias         string
 	Aliases       []string
@@ -24,9 +31,15 @@ type Server struct {
 	Port          int
 	IdentityFiles []string
 	Tags          []string
+	Notes         string            // free-form Markdown notes, stored in lazyssh metadata
+	Fields        map[string]string // custom key/value fields such as owner or rack, stored in lazyssh metadata
 	LastSeen      time.Time
 	PinnedAt      time.Time
 	SSHCount      int
+	LastPing      PingResult // outcome of the last ping this session; zero if not pinged
+	SourceFile    string     // config file defining the Host block (main config or an Included file)
+	Line          int        // line of the Host line in SourceFile
+	Effective     []EffectiveSetting
 
 	// Additional SSH config fields
 	// Connection and proxy settings
@@ -115,3 +128,23 @@ type Server struct {
 	// Debugging settings
 	LogLevel string
 }
+
+// SettingOrigin tells where an effective setting was obtained from.
+type SettingOrigin int
+
+const (
+	OriginOwnBlock   SettingOrigin = iota // the server's own Host block
+	OriginHostBlock                       // another Host block whose patterns match, e.g. Host *.prod
+	OriginMatchBlock                      // a Match block whose criteria match
+	OriginDefault                         // OpenSSH built-in default
+)
+
+// EffectiveSetting is a single value of the configuration ssh would apply to a server.
+type EffectiveSetting struct {
+	Key    string
+	Value  string
+	Origin SettingOrigin
+	Block  string // header of the block the value was read from, e.g. "Host *.prod"
+	File   string
+	Line   int
+}
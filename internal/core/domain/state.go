@@ -0,0 +1,24 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// UIState is what the interface remembers between sessions.
+type UIState struct {
+	SortMode      string `json:"sort_mode,omitempty"`
+	Query         string `json:"query,omitempty"`
+	SelectedAlias string `json:"selected_alias,omitempty"`
+	HelpMode      string `json:"help_mode,omitempty"`
+	Layout        string `json:"layout,omitempty"`
+}
@@ -17,10 +17,47 @@ package ports
 import "github.com/Adembc/lazyssh/internal/core/domain"
 
 type ServerRepository interface {
-	ListServers(query string) ([]domain.Server, error)
+	ListServers() ([]domain.Server, error)
+	ListAliases() ([]string, error)
+	ListTags() ([]string, error)
+	ResolveEffective(aliases []string) (map[string][]domain.EffectiveSetting, error)
 	UpdateServer(server domain.Server, newServer domain.Server) error
 	AddServer(server domain.Server) error
 	DeleteServer(server domain.Server) error
+	UpdateServers(servers []domain.Server, newServers []domain.Server) error
+	DeleteServers(servers []domain.Server) error
 	SetPinned(alias string, pinned bool) error
+	SetPinnedServers(aliases []string, pinned bool) error
 	RecordSSH(alias string) error
+	ListConfigFiles() ([]string, error)
+	LastConfigFile() (string, error)
+	ListProfiles() ([]domain.Profile, error)
+	AddProfile(profile domain.Profile) error
+	UpdateProfile(profile domain.Profile, newProfile domain.Profile) error
+	DeleteProfile(profile domain.Profile) error
+	ListMatchBlocks() ([]domain.MatchBlock, error)
+	Undo() (string, error)
+	Redo() (string, error)
+	ListBackups() ([]domain.Backup, error)
+	BackupDiff(backup domain.Backup) (string, error)
+	RestoreBackup(backup domain.Backup) error
+	Preview(change func() error) (string, error)
+	ReconcileMetadata() ([]domain.MetadataOrphan, error)
+	MigrateMetadata(oldAlias, newAlias string) error
+	PurgeMetadata(alias string) error
+	Watch(stop <-chan struct{}) <-chan []string
+	ConfigVersion() string
+}
+
+// SavedSearchRepository stores the searches the user saved under a name.
+type SavedSearchRepository interface {
+	ListSavedSearches() ([]domain.SavedSearch, error)
+	SaveSearch(search domain.SavedSearch) error
+	DeleteSavedSearch(name string) error
+}
+
+// StateRepository stores the UI state kept between sessions.
+type StateRepository interface {
+	LoadState() (domain.UIState, error)
+	SaveState(state domain.UIState) error
 }
@@ -22,10 +22,40 @@ import (
 
 type ServerService interface {
 	ListServers(query string) ([]domain.Server, error)
+	ListAliases() ([]string, error)
+	ListTags() ([]string, error)
+	ResolveEffective(aliases []string) (map[string][]domain.EffectiveSetting, error)
+	Lint() ([]domain.Finding, error)
+	SearchServers(query string) ([]domain.SearchResult, error)
+	CountServers(queries []string) ([]int, error)
+	ListSavedSearches() ([]domain.SavedSearch, error)
+	SaveSearch(search domain.SavedSearch) error
+	DeleteSavedSearch(name string) error
 	UpdateServer(server domain.Server, newServer domain.Server) error
 	AddServer(server domain.Server) error
 	DeleteServer(server domain.Server) error
+	UpdateServers(servers []domain.Server, newServers []domain.Server) error
+	DeleteServers(servers []domain.Server) error
 	SetPinned(alias string, pinned bool) error
-	SSH(alias string) error
+	SetPinnedServers(aliases []string, pinned bool) error
+	SSH(alias string, args ...string) error
 	Ping(server domain.Server) (bool, time.Duration, error)
+	ListConfigFiles() ([]string, error)
+	LastConfigFile() (string, error)
+	ListProfiles() ([]domain.Profile, error)
+	AddProfile(profile domain.Profile) error
+	UpdateProfile(profile domain.Profile, newProfile domain.Profile) error
+	DeleteProfile(profile domain.Profile) error
+	ListMatchBlocks() ([]domain.MatchBlock, error)
+	Undo() (string, error)
+	Redo() (string, error)
+	ListBackups() ([]domain.Backup, error)
+	BackupDiff(backup domain.Backup) (string, error)
+	RestoreBackup(backup domain.Backup) error
+	Preview(change func() error) (string, error)
+	ReconcileMetadata() ([]domain.MetadataOrphan, error)
+	MigrateMetadata(oldAlias, newAlias string) error
+	PurgeMetadata(alias string) error
+	Watch(stop <-chan struct{}) <-chan []string
+	ConfigVersion() string
 }
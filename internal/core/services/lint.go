@@ -0,0 +1,298 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/fsutil"
+)
+
+// deprecatedOptions are options OpenSSH renamed or no longer supports, by lower-case keyword.
+var deprecatedOptions = map[string]string{
+	"pubkeyacceptedkeytypes":          "renamed to PubkeyAcceptedAlgorithms in OpenSSH 8.5",
+	"hostbasedkeytypes":               "renamed to HostbasedAcceptedAlgorithms in OpenSSH 8.5",
+	"hostbasedacceptedkeytypes":       "renamed to HostbasedAcceptedAlgorithms in OpenSSH 8.5",
+	"challengeresponseauthentication": "is a deprecated alias of KbdInteractiveAuthentication",
+	"dsaauthentication":               "is a deprecated alias of PubkeyAuthentication",
+	"keepalive":                       "is a deprecated alias of TCPKeepAlive",
+	"useroaming":                      "was removed in OpenSSH 7.2 and is ignored",
+	"useprivilegedport":               "is no longer supported and is ignored",
+	"protocol":                        "is ignored since SSH protocol 1 was removed in OpenSSH 7.6",
+	"cipher":                          "is an SSH protocol 1 option, ignored since OpenSSH 7.6",
+	"compressionlevel":                "is an SSH protocol 1 option, ignored since OpenSSH 7.6",
+	"rsaauthentication":               "is an SSH protocol 1 option, ignored since OpenSSH 7.6",
+	"rhostsrsaauthentication":         "is an SSH protocol 1 option, ignored since OpenSSH 7.6",
+}
+
+// lintedFields are the form fields whose validators are run over every server.
+var lintedFields = []struct {
+	name   string
+	values func(s domain.Server) []string
+}{
+	{"Port", func(s domain.Server) []string { return []string{strconv.Itoa(s.Port)} }},
+	{"User", func(s domain.Server) []string { return []string{s.User} }},
+	{"ConnectTimeout", func(s domain.Server) []string { return []string{s.ConnectTimeout} }},
+	{"ConnectionAttempts", func(s domain.Server) []string { return []string{s.ConnectionAttempts} }},
+	{"ServerAliveInterval", func(s domain.Server) []string { return []string{s.ServerAliveInterval} }},
+	{"ServerAliveCountMax", func(s domain.Server) []string { return []string{s.ServerAliveCountMax} }},
+	{"IPQoS", func(s domain.Server) []string { return []string{s.IPQoS} }},
+	{"BindAddress", func(s domain.Server) []string { return []string{s.BindAddress} }},
+	{"LocalForward", func(s domain.Server) []string { return s.LocalForward }},
+	{"RemoteForward", func(s domain.Server) []string { return s.RemoteForward }},
+	{"DynamicForward", func(s domain.Server) []string { return s.DynamicForward }},
+	{"NumberOfPasswordPrompts", func(s domain.Server) []string { return []string{s.NumberOfPasswordPrompts} }},
+	{"CanonicalizeMaxDots", func(s domain.Server) []string { return []string{s.CanonicalizeMaxDots} }},
+	{"EscapeChar", func(s domain.Server) []string { return []string{s.EscapeChar} }},
+}
+
+// LintServers checks the SSH config of servers for common mistakes: missing or world-readable
+// identity files, aliases defined twice, ProxyJump hops that are unknown or loop, deprecated
+// options, ControlMaster without a usable ControlPath and values the server form would reject.
+// Servers must be given in the order ssh reads them. Findings are sorted by file and line.
+func LintServers(servers []domain.Server) []domain.Finding {
+	l := &linter{byAlias: make(map[string]domain.Server, len(servers))}
+	shadowed := make([]bool, len(servers))
+	for i, server := range servers {
+		if first, ok := l.byAlias[server.Alias]; ok {
+			// ssh takes every option from the first block that sets it, so the rest of this
+			// block only applies where the first one is silent.
+			l.addRedefined(server, server.Alias, first)
+			shadowed[i] = true
+			continue
+		}
+		l.byAlias[server.Alias] = server
+		for _, alias := range server.Aliases {
+			if first, ok := l.byAlias[alias]; !ok {
+				l.byAlias[alias] = server
+			} else if alias != server.Alias {
+				l.addRedefined(server, alias, first)
+			}
+		}
+	}
+	for i, server := range servers {
+		if shadowed[i] {
+			continue
+		}
+		l.lintIdentityFiles(server)
+		l.lintProxyJump(server)
+		l.lintMultiplexing(server)
+		l.lintOptions(server)
+	}
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		a, b := l.findings[i], l.findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return l.findings
+}
+
+type linter struct {
+	byAlias  map[string]domain.Server
+	findings []domain.Finding
+}
+
+func (l *linter) add(server domain.Server, severity domain.Severity, file string, line int, message string) {
+	l.findings = append(l.findings, domain.Finding{
+		Severity: severity,
+		Alias:    server.Alias,
+		Message:  message,
+		File:     file,
+		Line:     line,
+	})
+}
+
+// addRedefined reports that the Host block of server names alias, which first defines.
+func (l *linter) addRedefined(server domain.Server, alias string, first domain.Server) {
+	l.add(server, domain.SeverityWarning, server.SourceFile, server.Line,
+		fmt.Sprintf("alias %s is already defined at %s:%d; options set there take precedence",
+			alias, fsutil.ShortenHome(first.SourceFile), first.Line))
+}
+
+// addAt reports a finding at the line that sets key to value, or at the first line setting key
+// when value is empty or not found, or at the Host line if no block sets key.
+func (l *linter) addAt(server domain.Server, severity domain.Severity, key, value, message string) {
+	setting, ok := findSetting(server, key, value)
+	if !ok {
+		l.add(server, severity, server.SourceFile, server.Line, message)
+		return
+	}
+	l.add(server, severity, setting.File, setting.Line, message)
+}
+
+func (l *linter) lintIdentityFiles(server domain.Server) {
+	for _, path := range server.IdentityFiles {
+		// Tokens and environment variables are expanded by ssh at connection time.
+		if strings.ContainsAny(path, "%$") {
+			continue
+		}
+		exists, accessible, isDir := validateFilePath(path)
+		switch {
+		case !exists:
+			l.addAt(server, domain.SeverityError, "IdentityFile", path, fmt.Sprintf("IdentityFile %s does not exist", path))
+			continue
+		case isDir:
+			l.addAt(server, domain.SeverityError, "IdentityFile", path, fmt.Sprintf("IdentityFile %s is a directory", path))
+			continue
+		case !accessible:
+			l.addAt(server, domain.SeverityError, "IdentityFile", path, fmt.Sprintf("IdentityFile %s is not readable", path))
+			continue
+		}
+		if info, err := os.Stat(fsutil.ExpandHome(path)); err == nil && info.Mode().Perm()&0o077 != 0 {
+			l.addAt(server, domain.SeverityError, "IdentityFile", path,
+				fmt.Sprintf("IdentityFile %s has mode %04o; ssh ignores private keys others can read, run chmod 600 on it", path, info.Mode().Perm()))
+		}
+	}
+}
+
+func (l *linter) lintProxyJump(server domain.Server) {
+	if server.ProxyJump == "" || strings.EqualFold(server.ProxyJump, "none") {
+		return
+	}
+	for _, hop := range proxyJumpHosts(server.ProxyJump) {
+		if _, ok := l.byAlias[hop]; !ok && !strings.ContainsAny(hop, ".:") && hop != "localhost" {
+			l.addAt(server, domain.SeverityWarning, "ProxyJump", "", fmt.Sprintf("ProxyJump host %s is not a known alias", hop))
+		}
+	}
+	if path := l.jumpLoop(server.Alias, server.Alias, map[string]bool{}); path != nil {
+		l.addAt(server, domain.SeverityError, "ProxyJump", "",
+			fmt.Sprintf("ProxyJump loops back to %s: %s", server.Alias, strings.Join(append([]string{server.Alias}, path...), " → ")))
+	}
+}
+
+// jumpLoop returns the hops from alias that lead back to target through ProxyJump, or nil.
+func (l *linter) jumpLoop(alias, target string, visited map[string]bool) []string {
+	if visited[alias] {
+		return nil
+	}
+	visited[alias] = true
+	server, ok := l.byAlias[alias]
+	if !ok || strings.EqualFold(server.ProxyJump, "none") {
+		return nil
+	}
+	for _, hop := range proxyJumpHosts(server.ProxyJump) {
+		if hop == target {
+			return []string{hop}
+		}
+		if path := l.jumpLoop(hop, target, visited); path != nil {
+			return append([]string{hop}, path...)
+		}
+	}
+	return nil
+}
+
+// lintMultiplexing checks the effective ControlMaster, ControlPath and ControlPersist settings,
+// which often come from a shared Host * block.
+func (l *linter) lintMultiplexing(server domain.Server) {
+	master := strings.ToLower(effectiveValue(server, "ControlMaster"))
+	path := effectiveValue(server, "ControlPath")
+	persist := strings.ToLower(effectiveValue(server, "ControlPersist"))
+
+	switch master {
+	case "yes", "auto", "ask", "autoask":
+		switch {
+		case path == "" || strings.EqualFold(path, "none"):
+			l.addAt(server, domain.SeverityError, "ControlMaster", "",
+				fmt.Sprintf("ControlMaster %s needs a ControlPath for the master connection's socket", master))
+		case !strings.Contains(path, "%"):
+			l.addAt(server, domain.SeverityWarning, "ControlPath", "",
+				fmt.Sprintf("ControlPath %s has no %%C or %%h/%%p/%%r token, so connections to different hosts share one socket", path))
+		}
+	default:
+		if persist != "" && persist != "no" {
+			l.addAt(server, domain.SeverityWarning, "ControlPersist", "", "ControlPersist has no effect without ControlMaster")
+		}
+	}
+}
+
+// lintOptions reports deprecated options in the server's own block and values that the
+// validators of the server form reject.
+func (l *linter) lintOptions(server domain.Server) {
+	for _, setting := range server.Effective {
+		if setting.Origin != domain.OriginOwnBlock {
+			continue
+		}
+		if advice, ok := deprecatedOptions[strings.ToLower(setting.Key)]; ok {
+			l.add(server, domain.SeverityWarning, setting.File, setting.Line, fmt.Sprintf("%s %s", setting.Key, advice))
+		}
+	}
+	for _, field := range lintedFields {
+		for _, value := range field.values(server) {
+			if err := ValidateField(field.name, value); err != nil {
+				l.addAt(server, domain.SeverityWarning, field.name, "", fmt.Sprintf("%s %s: %v", field.name, value, err))
+			}
+		}
+	}
+}
+
+// findSetting returns where key is set for the server, preferring the line that sets it to value.
+func findSetting(server domain.Server, key, value string) (domain.EffectiveSetting, bool) {
+	var first *domain.EffectiveSetting
+	for i, setting := range server.Effective {
+		if setting.Origin == domain.OriginDefault || !strings.EqualFold(setting.Key, key) {
+			continue
+		}
+		if value != "" && setting.Value == value {
+			return setting, true
+		}
+		if first == nil {
+			first = &server.Effective[i]
+		}
+	}
+	if first == nil {
+		return domain.EffectiveSetting{}, false
+	}
+	return *first, true
+}
+
+// effectiveValue returns the value ssh applies for key, including OpenSSH defaults.
+func effectiveValue(server domain.Server, key string) string {
+	for _, setting := range server.Effective {
+		if strings.EqualFold(setting.Key, key) {
+			return setting.Value
+		}
+	}
+	return ""
+}
+
+// proxyJumpHosts returns the host of every hop of a ProxyJump value, without user or port.
+func proxyJumpHosts(value string) []string {
+	hosts := make([]string, 0)
+	for _, hop := range strings.Split(value, ",") {
+		hop = strings.TrimPrefix(strings.TrimSpace(hop), "ssh://")
+		if at := strings.LastIndex(hop, "@"); at >= 0 {
+			hop = hop[at+1:]
+		}
+		if strings.HasPrefix(hop, "[") {
+			if end := strings.Index(hop, "]"); end > 0 {
+				hop = hop[1:end]
+			}
+		} else if colon := strings.LastIndex(hop, ":"); colon >= 0 && strings.Count(hop, ":") == 1 {
+			hop = hop[:colon]
+		}
+		if hop != "" {
+			hosts = append(hosts, hop)
+		}
+	}
+	return hosts
+}
@@ -0,0 +1,111 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestLintServers(t *testing.T) {
+	dir := t.TempDir()
+	key := filepath.Join(dir, "id_ed25519")
+	openKey := filepath.Join(dir, "id_open")
+	for path, mode := range map[string]os.FileMode{key: 0o600, openKey: 0o644} {
+		if err := os.WriteFile(path, []byte("key"), mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	own := func(k, v string, line int) domain.EffectiveSetting {
+		return domain.EffectiveSetting{Key: k, Value: v, Origin: domain.OriginOwnBlock, File: "config", Line: line}
+	}
+	server := func(alias string, line int, configure func(*domain.Server)) domain.Server {
+		s := domain.Server{Alias: alias, Aliases: []string{alias}, Port: 22, SourceFile: "config", Line: line}
+		if configure != nil {
+			configure(&s)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		servers []domain.Server
+		want    []string // "alias:line:severity:message fragment"
+	}{
+		{"clean", []domain.Server{server("web", 1, func(s *domain.Server) {
+			s.IdentityFiles = []string{key, "~/.ssh/%h"}
+			s.ProxyJump = "deploy@bastion.example.com:2222"
+		})}, nil},
+		{"identity files", []domain.Server{server("web", 1, func(s *domain.Server) {
+			s.IdentityFiles = []string{filepath.Join(dir, "missing"), openKey}
+			s.Effective = []domain.EffectiveSetting{own("IdentityFile", filepath.Join(dir, "missing"), 2), own("IdentityFile", openKey, 3)}
+		})}, []string{"web:2:error:does not exist", "web:3:error:mode 0644"}},
+		{"duplicate alias", []domain.Server{server("web", 1, nil), server("db", 4, nil), server("web", 7, nil)},
+			[]string{"web:7:warning:already defined at config:1"}},
+		{"duplicate secondary alias", []domain.Server{
+			server("b", 1, nil),
+			server("a", 3, func(s *domain.Server) { s.Aliases = []string{"a", "b"} }),
+		}, []string{"a:3:warning:alias b is already defined at config:1"}},
+		{"proxy jump", []domain.Server{
+			server("a", 1, func(s *domain.Server) { s.ProxyJump = "b" }),
+			server("b", 3, func(s *domain.Server) { s.ProxyJump = "user@a:22" }),
+			server("c", 5, func(s *domain.Server) { s.ProxyJump = "bastion" }),
+		}, []string{"a:1:error:a → b → a", "b:3:error:b → a → b", "c:5:warning:bastion is not a known alias"}},
+		{"deprecated option", []domain.Server{server("web", 1, func(s *domain.Server) {
+			s.Effective = []domain.EffectiveSetting{own("PubkeyAcceptedKeyTypes", "+ssh-rsa", 2)}
+		})}, []string{"web:2:warning:PubkeyAcceptedAlgorithms"}},
+		{"multiplexing", []domain.Server{
+			server("a", 1, func(s *domain.Server) { s.Effective = []domain.EffectiveSetting{own("ControlMaster", "auto", 2)} }),
+			server("b", 3, func(s *domain.Server) {
+				s.Effective = []domain.EffectiveSetting{own("ControlMaster", "yes", 4), own("ControlPath", "/tmp/ssh.sock", 5)}
+			}),
+			server("c", 6, func(s *domain.Server) {
+				s.Effective = []domain.EffectiveSetting{own("ControlPersist", "10m", 7), {Key: "ControlMaster", Value: "no", Origin: domain.OriginDefault}}
+			}),
+		}, []string{"a:2:error:needs a ControlPath", "b:5:warning:share one socket", "c:7:warning:ControlPersist has no effect"}},
+		{"form validators", []domain.Server{server("web", 1, func(s *domain.Server) {
+			s.LocalForward = []string{"8080:localhost"}
+			s.ConnectTimeout = "soon"
+		})}, []string{"web:1:warning:ConnectTimeout soon", "web:1:warning:LocalForward 8080:localhost"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := LintServers(tt.servers)
+			if len(findings) != len(tt.want) {
+				t.Fatalf("LintServers() = %+v, want %d findings", findings, len(tt.want))
+			}
+			for _, want := range tt.want {
+				parts := strings.SplitN(want, ":", 4)
+				found := false
+				for _, f := range findings {
+					if f.Alias == parts[0] && parts[1] == strconv.Itoa(f.Line) && f.Severity.String() == parts[2] && strings.Contains(f.Message, parts[3]) {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("no finding %q in %+v", want, findings)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// Scores of a fuzzy match: every matched rune earns scoreMatch, plus scoreConsecutive when it
+// directly follows the previous match and scoreBoundary when it starts a word. Every rune
+// skipped between two matches costs scoreGap, and matching the whole text earns scoreExact.
+const (
+	scoreMatch       = 16
+	scoreConsecutive = 16
+	scoreBoundary    = 8
+	scoreGap         = 1
+	scoreExact       = 32
+)
+
+// Weights of the fields a search term is matched against.
+const (
+	weightAlias = 3
+	weightHost  = 2
+	weightOther = 1
+)
+
+// searchQualifiers are the fields a query can filter on with "field:value".
+var searchQualifiers = map[string]bool{
+	"alias":  true,
+	"host":   true,
+	"user":   true,
+	"port":   true,
+	"tag":    true,
+	"pinned": true,
+	"seen":   true,
+	"status": true,
+}
+
+// searchFilter is a "field:value" qualifier, or a "-term" exclusion when field is empty.
+type searchFilter struct {
+	field  string
+	value  string
+	negate bool
+}
+
+// searchQuery is a parsed search: every term must fuzzy-match and every filter must hold.
+type searchQuery struct {
+	terms   []string
+	filters []searchFilter
+}
+
+// parseSearchQuery splits a query into fuzzy terms and filters. Words of the form field:value
+// with a known field are qualifiers, e.g. "tag:prod user:root port:2222 host:10.0.* pinned:yes";
+// seen:7d keeps servers connected to in the last 7 days (seen:never those never connected to)
+// and status:up or status:down filters on the last ping. A leading "-" negates a qualifier or
+// excludes servers containing a term. Qualifiers without a value, as while one is being typed,
+// are ignored.
+func parseSearchQuery(query string) searchQuery {
+	var q searchQuery
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		negate := len(word) > 1 && strings.HasPrefix(word, "-")
+		if negate {
+			word = word[1:]
+		}
+		if field, value, ok := strings.Cut(word, ":"); ok && searchQualifiers[field] {
+			if value != "" {
+				q.filters = append(q.filters, searchFilter{field: field, value: value, negate: negate})
+			}
+			continue
+		}
+		if negate {
+			q.filters = append(q.filters, searchFilter{value: word, negate: true})
+			continue
+		}
+		q.terms = append(q.terms, word)
+	}
+	return q
+}
+
+// searchServers returns the servers matching query, best matches first. Servers that match
+// equally well keep their order.
+func searchServers(servers []domain.Server, query string) []domain.SearchResult {
+	q := parseSearchQuery(query)
+	results := make([]domain.SearchResult, 0)
+	for _, server := range servers {
+		if result, ok := q.match(server); ok {
+			results = append(results, result)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results
+}
+
+// match reports whether server satisfies q, scoring it and recording what to highlight.
+func (q searchQuery) match(server domain.Server) (domain.SearchResult, bool) {
+	result := domain.SearchResult{Server: server}
+	for _, filter := range q.filters {
+		if filter.matches(server) == filter.negate {
+			return result, false
+		}
+	}
+
+	alias := strings.ToLower(server.Alias)
+	host := strings.ToLower(server.Host)
+	for _, term := range q.terms {
+		best := 0
+		if score, positions, ok := fuzzyMatch(alias, term); ok {
+			best = max(best, score*weightAlias)
+			result.AliasMatches = append(result.AliasMatches, positions...)
+		}
+		if score, positions, ok := fuzzyMatch(host, term); ok {
+			best = max(best, score*weightHost)
+			result.HostMatches = append(result.HostMatches, positions...)
+		}
+		for _, field := range otherSearchFields(server) {
+			if score, _, ok := fuzzyMatch(field, term); ok {
+				best = max(best, score*weightOther)
+			}
+		}
+		if best == 0 && containsTerm(server, term) {
+			best = utf8.RuneCountInString(term)
+		}
+		if best == 0 {
+			return result, false
+		}
+		result.Score += best
+	}
+	result.AliasMatches = uniqueSorted(result.AliasMatches)
+	result.HostMatches = uniqueSorted(result.HostMatches)
+	return result, true
+}
+
+// matches reports whether server has the value the filter asks for. Values compare case
+// insensitively and may use the wildcards * and ?; an exclusion matches anywhere as a substring.
+func (f searchFilter) matches(server domain.Server) bool {
+	switch f.field {
+	case "":
+		return containsTerm(server, f.value)
+	case "alias":
+		aliases := server.Aliases
+		if len(aliases) == 0 {
+			aliases = []string{server.Alias}
+		}
+		return matchesAny(f.value, aliases)
+	case "host":
+		host := server.Host
+		if host == "" {
+			host = server.Alias
+		}
+		return matchesValue(f.value, host)
+	case "user":
+		return matchesValue(f.value, server.User)
+	case "port":
+		return matchesValue(f.value, strconv.Itoa(server.Port))
+	case "tag":
+		return matchesAny(f.value, server.Tags)
+	case "pinned":
+		switch f.value {
+		case "yes", "true", "1":
+			return !server.PinnedAt.IsZero()
+		case "no", "false", "0":
+			return server.PinnedAt.IsZero()
+		}
+	case "seen":
+		if f.value == "never" {
+			return server.LastSeen.IsZero()
+		}
+		if within, ok := parseAge(f.value); ok {
+			return !server.LastSeen.IsZero() && time.Since(server.LastSeen) <= within
+		}
+	case "status":
+		pinged := !server.LastPing.At.IsZero()
+		switch f.value {
+		case "up":
+			return pinged && server.LastPing.Up
+		case "down":
+			return pinged && !server.LastPing.Up
+		}
+	}
+	return false
+}
+
+// parseAge parses a duration such as "12h" or a number of days such as "7d".
+func parseAge(value string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		return time.Duration(n) * 24 * time.Hour, err == nil && n >= 0
+	}
+	d, err := time.ParseDuration(value)
+	return d, err == nil && d >= 0
+}
+
+func matchesAny(pattern string, values []string) bool {
+	for _, value := range values {
+		if matchesValue(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesValue matches the lowercase pattern against the whole of value.
+func matchesValue(pattern, value string) bool {
+	value = strings.ToLower(value)
+	if ok, err := path.Match(pattern, value); err == nil {
+		return ok
+	}
+	return pattern == value
+}
+
+// otherSearchFields lists the lowercase short fields besides the alias and host that terms fuzzy-match.
+func otherSearchFields(server domain.Server) []string {
+	fields := make([]string, 0, len(server.Aliases)+len(server.Tags)+1)
+	if server.User != "" {
+		fields = append(fields, strings.ToLower(server.User))
+	}
+	for _, alias := range server.Aliases {
+		if alias != server.Alias {
+			fields = append(fields, strings.ToLower(alias))
+		}
+	}
+	for _, tag := range server.Tags {
+		fields = append(fields, strings.ToLower(tag))
+	}
+	return fields
+}
+
+// containsTerm reports whether term appears in any field of server, including notes and
+// custom fields, which are too long for fuzzy matching to be meaningful.
+func containsTerm(server domain.Server, term string) bool {
+	fields := append(otherSearchFields(server), strings.ToLower(server.Alias), strings.ToLower(server.Host), strings.ToLower(server.Notes))
+	for key, value := range server.Fields {
+		fields = append(fields, strings.ToLower(key), strings.ToLower(value))
+	}
+	for _, field := range fields {
+		if strings.Contains(field, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyMatch reports whether the runes of pattern appear in text in order, and returns the
+// score of the best alignment found together with the rune offsets in text that matched.
+// An alignment is tried from every occurrence of the first rune of pattern, each time
+// matching the remaining runes as early as possible.
+func fuzzyMatch(text, pattern string) (int, []int, bool) {
+	t, p := []rune(text), []rune(pattern)
+	if len(p) == 0 || len(p) > len(t) {
+		return 0, nil, false
+	}
+
+	bestScore, found := 0, false
+	var best []int
+	for start := range t {
+		if t[start] != p[0] {
+			continue
+		}
+		positions := []int{start}
+		for i, j := start+1, 1; j < len(p) && i < len(t); i++ {
+			if t[i] == p[j] {
+				positions = append(positions, i)
+				j++
+			}
+		}
+		if len(positions) < len(p) {
+			break
+		}
+		if score := alignmentScore(t, positions); !found || score > bestScore {
+			bestScore, best, found = score, positions, true
+		}
+	}
+	return bestScore, best, found
+}
+
+func alignmentScore(text []rune, positions []int) int {
+	score := 0
+	for i, pos := range positions {
+		score += scoreMatch
+		if pos == 0 || strings.ContainsRune(" -_./@:", text[pos-1]) {
+			score += scoreBoundary
+		}
+		if i > 0 {
+			if gap := pos - positions[i-1] - 1; gap == 0 {
+				score += scoreConsecutive
+			} else {
+				score -= gap * scoreGap
+			}
+		}
+	}
+	if len(positions) == len(text) {
+		score += scoreExact
+	}
+	return max(score, 1)
+}
+
+func uniqueSorted(positions []int) []int {
+	if len(positions) == 0 {
+		return nil
+	}
+	sort.Ints(positions)
+	unique := positions[:1]
+	for _, pos := range positions[1:] {
+		if pos != unique[len(unique)-1] {
+			unique = append(unique, pos)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,94 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestSearchServers(t *testing.T) {
+	servers := []domain.Server{
+		{Alias: "web-prod", Aliases: []string{"web-prod"}, Host: "10.0.0.1", User: "deploy", Port: 22, Tags: []string{"prod", "web"}, PinnedAt: time.Now()},
+		{Alias: "db", Aliases: []string{"db", "postgres"}, Host: "10.0.1.5", User: "root", Port: 2222, Tags: []string{"prod"},
+			LastSeen: time.Now().Add(-time.Hour), LastPing: domain.PingResult{Up: false, At: time.Now()}},
+		{Alias: "legacy-web", Aliases: []string{"legacy-web"}, Host: "old.example.com", User: "root", Port: 22, Tags: []string{"legacy"},
+			Notes: "Restart with `systemctl restart nginx`", Fields: map[string]string{"owner": "alice"}},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"web", []string{"web-prod", "legacy-web"}},
+		{"wbp", []string{"web-prod"}},
+		{"lw", []string{"legacy-web"}},
+		{"postgres", []string{"db"}},
+		{"nginx", []string{"legacy-web"}},
+		{"ALICE", []string{"legacy-web"}},
+		{"tag:prod", []string{"web-prod", "db"}},
+		{"-tag:legacy", []string{"web-prod", "db"}},
+		{"user:root port:2222", []string{"db"}},
+		{"host:10.0.*", []string{"web-prod", "db"}},
+		{"pinned:yes", []string{"web-prod"}},
+		{"pinned:no web", []string{"legacy-web"}},
+		{"web -legacy", []string{"web-prod"}},
+		{"tag:", []string{"web-prod", "db", "legacy-web"}},
+		{"seen:1d", []string{"db"}},
+		{"seen:never", []string{"web-prod", "legacy-web"}},
+		{"status:down", []string{"db"}},
+		{"status:up", nil},
+		{"xyz", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, result := range searchServers(servers, tt.query) {
+			got = append(got, result.Server.Alias)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("searchServers(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		text, pattern string
+		want          []int
+		ok            bool
+	}{
+		{"web-prod", "wp", []int{0, 4}, true},
+		{"web-prod", "prod", []int{4, 5, 6, 7}, true},
+		{"api-app", "ap", []int{0, 1}, true},
+		{"web", "bew", nil, false},
+		{"web", "webs", nil, false},
+	}
+	for _, tt := range tests {
+		_, got, ok := fuzzyMatch(tt.text, tt.pattern)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, %v, want %v, %v", tt.text, tt.pattern, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	exact, _, _ := fuzzyMatch("db", "db")
+	scattered, _, _ := fuzzyMatch("dashboard", "db")
+	if exact <= scattered {
+		t.Errorf("exact match scored %d, not above scattered match %d", exact, scattered)
+	}
+}
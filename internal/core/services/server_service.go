@@ -24,6 +24,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
@@ -33,24 +34,49 @@ import (
 
 type serverService struct {
 	serverRepository ports.ServerRepository
+	searchRepository ports.SavedSearchRepository
 	logger           *zap.SugaredLogger
+
+	// pings holds the last ping result of each alias in this session.
+	pingsMu sync.Mutex
+	pings   map[string]domain.PingResult
 }
 
 // NewServerService creates a new instance of serverService.
-func NewServerService(logger *zap.SugaredLogger, sr ports.ServerRepository) ports.ServerService {
+func NewServerService(logger *zap.SugaredLogger, sr ports.ServerRepository, ssr ports.SavedSearchRepository) ports.ServerService {
 	return &serverService{
 		logger:           logger,
 		serverRepository: sr,
+		searchRepository: ssr,
+		pings:            make(map[string]domain.PingResult),
 	}
 }
 
-// ListServers returns a list of servers sorted with pinned on top.
+// ListServers returns the servers matching query. Without a query they are sorted with pinned
+// on top; with one they are ranked by how well they match.
 func (s *serverService) ListServers(query string) ([]domain.Server, error) {
-	servers, err := s.serverRepository.ListServers(query)
+	if strings.TrimSpace(query) != "" {
+		results, err := s.SearchServers(query)
+		if err != nil {
+			return nil, err
+		}
+		servers := make([]domain.Server, len(results))
+		for i, result := range results {
+			servers[i] = result.Server
+		}
+		return servers, nil
+	}
+
+	servers, err := s.serverRepository.ListServers()
 	if err != nil {
 		s.logger.Errorw("failed to list servers", "error", err)
 		return nil, err
 	}
+	s.pingsMu.Lock()
+	for i := range servers {
+		servers[i].LastPing = s.pings[servers[i].Alias]
+	}
+	s.pingsMu.Unlock()
 
 	// Sort: pinned first (PinnedAt non-zero), then by PinnedAt desc, then by Alias asc.
 	sort.SliceStable(servers, func(i, j int) bool {
@@ -68,6 +94,116 @@ func (s *serverService) ListServers(query string) ([]domain.Server, error) {
 	return servers, nil
 }
 
+// ListAliases returns every server alias, sorted. It is cheap enough for shell completion.
+func (s *serverService) ListAliases() ([]string, error) {
+	aliases, err := s.serverRepository.ListAliases()
+	if err != nil {
+		s.logger.Errorw("failed to list aliases", "error", err)
+	}
+	return aliases, err
+}
+
+// ListTags returns every tag in use, sorted. It is cheap enough for shell completion.
+func (s *serverService) ListTags() ([]string, error) {
+	tags, err := s.serverRepository.ListTags()
+	if err != nil {
+		s.logger.Errorw("failed to list tags", "error", err)
+	}
+	return tags, err
+}
+
+// ResolveEffective returns the configuration ssh would apply to each of aliases, with the
+// origin of every value.
+func (s *serverService) ResolveEffective(aliases []string) (map[string][]domain.EffectiveSetting, error) {
+	effective, err := s.serverRepository.ResolveEffective(aliases)
+	if err != nil {
+		s.logger.Errorw("failed to resolve effective config", "error", err)
+	}
+	return effective, err
+}
+
+// Lint checks every server with LintServers. Servers are checked in the order ssh reads
+// them, which decides which of two blocks naming the same alias takes precedence. The
+// effective configuration the checks need is resolved for all servers in one pass over the
+// config files.
+func (s *serverService) Lint() ([]domain.Finding, error) {
+	servers, err := s.serverRepository.ListServers()
+	if err != nil {
+		s.logger.Errorw("failed to list servers", "error", err)
+		return nil, err
+	}
+	aliases := make([]string, len(servers))
+	for i, server := range servers {
+		aliases[i] = server.Alias
+	}
+	effective, err := s.ResolveEffective(aliases)
+	if err != nil {
+		return nil, err
+	}
+	for i := range servers {
+		servers[i].Effective = effective[servers[i].Alias]
+	}
+	return LintServers(servers), nil
+}
+
+// SearchServers returns the servers matching query, best matches first, with the characters
+// that matched. Besides fuzzy terms the query accepts qualifiers such as tag:prod or -user:root.
+func (s *serverService) SearchServers(query string) ([]domain.SearchResult, error) {
+	servers, err := s.ListServers("")
+	if err != nil {
+		return nil, err
+	}
+	return searchServers(servers, query), nil
+}
+
+// CountServers returns how many servers match each of queries.
+func (s *serverService) CountServers(queries []string) ([]int, error) {
+	servers, err := s.ListServers("")
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]int, len(queries))
+	for i, query := range queries {
+		counts[i] = len(searchServers(servers, query))
+	}
+	return counts, nil
+}
+
+// ListSavedSearches returns the searches the user saved, in the order they were saved.
+func (s *serverService) ListSavedSearches() ([]domain.SavedSearch, error) {
+	searches, err := s.searchRepository.ListSavedSearches()
+	if err != nil {
+		s.logger.Errorw("failed to list saved searches", "error", err)
+	}
+	return searches, err
+}
+
+// SaveSearch saves a search under its name, replacing the query of a search with the same name.
+func (s *serverService) SaveSearch(search domain.SavedSearch) error {
+	search.Name = strings.TrimSpace(search.Name)
+	search.Query = strings.TrimSpace(search.Query)
+	if search.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if search.Query == "" {
+		return fmt.Errorf("query is required")
+	}
+	err := s.searchRepository.SaveSearch(search)
+	if err != nil {
+		s.logger.Errorw("failed to save search", "error", err, "name", search.Name)
+	}
+	return err
+}
+
+// DeleteSavedSearch removes the saved search with the given name.
+func (s *serverService) DeleteSavedSearch(name string) error {
+	err := s.searchRepository.DeleteSavedSearch(name)
+	if err != nil {
+		s.logger.Errorw("failed to delete saved search", "error", err, "name", name)
+	}
+	return err
+}
+
 // validateServer performs core validation of server fields.
 func validateServer(srv domain.Server) error {
 	if strings.TrimSpace(srv.Alias) == "" {
@@ -148,10 +284,237 @@ func (s *serverService) SetPinned(alias string, pinned bool) error {
 	return err
 }
 
+// UpdateServers updates several servers in one change; nothing is written if any new version is invalid.
+func (s *serverService) UpdateServers(servers []domain.Server, newServers []domain.Server) error {
+	for _, newServer := range newServers {
+		if err := validateServer(newServer); err != nil {
+			s.logger.Warnw("validation failed on bulk update", "error", err, "server", newServer)
+			return fmt.Errorf("%s: %w", newServer.Alias, err)
+		}
+	}
+	err := s.serverRepository.UpdateServers(servers, newServers)
+	if err != nil {
+		s.logger.Errorw("failed to update servers", "error", err, "count", len(servers))
+	}
+	return err
+}
+
+// DeleteServers removes several servers in one change.
+func (s *serverService) DeleteServers(servers []domain.Server) error {
+	err := s.serverRepository.DeleteServers(servers)
+	if err != nil {
+		s.logger.Errorw("failed to delete servers", "error", err, "count", len(servers))
+	}
+	return err
+}
+
+// SetPinnedServers pins or unpins several servers in one change.
+func (s *serverService) SetPinnedServers(aliases []string, pinned bool) error {
+	err := s.serverRepository.SetPinnedServers(aliases, pinned)
+	if err != nil {
+		s.logger.Errorw("failed to set pin state", "error", err, "aliases", aliases, "pinned", pinned)
+	}
+	return err
+}
+
+// ListConfigFiles returns the config files new servers can be written to.
+func (s *serverService) ListConfigFiles() ([]string, error) {
+	files, err := s.serverRepository.ListConfigFiles()
+	if err != nil {
+		s.logger.Errorw("failed to list config files", "error", err)
+	}
+	return files, err
+}
+
+// LastConfigFile returns the config file most recently chosen for a new server.
+func (s *serverService) LastConfigFile() (string, error) {
+	file, err := s.serverRepository.LastConfigFile()
+	if err != nil {
+		s.logger.Warnw("failed to read last config file", "error", err)
+	}
+	return file, err
+}
+
+// ListProfiles returns the pattern Host blocks and the servers each one applies to.
+func (s *serverService) ListProfiles() ([]domain.Profile, error) {
+	profiles, err := s.serverRepository.ListProfiles()
+	if err != nil {
+		s.logger.Errorw("failed to list profiles", "error", err)
+		return nil, err
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i].Alias < profiles[j].Alias
+	})
+	return profiles, nil
+}
+
+// validateProfile performs core validation of profile fields.
+// Unlike servers, profiles need no HostName and every one of their patterns has a wildcard or is negated.
+func validateProfile(profile domain.Profile) error {
+	patterns := strings.Fields(profile.Alias)
+	if len(patterns) == 0 {
+		return fmt.Errorf("at least one host pattern is required")
+	}
+	for _, pattern := range patterns {
+		if ok, _ := regexp.MatchString(`^!?[A-Za-z0-9_.*?-]+$`, pattern); !ok {
+			return fmt.Errorf("pattern %q may contain letters, digits, dot, dash, underscore, * and ?, optionally prefixed with !", pattern)
+		}
+		// A Host block is only read back as a profile if none of its patterns is a
+		// concrete alias; a concrete one would turn the block into a server.
+		if !strings.ContainsAny(pattern, "!*?[]") {
+			return fmt.Errorf("pattern %q has no wildcard; every pattern of a profile needs * or ? or a leading !, add a server for %q instead", pattern, pattern)
+		}
+	}
+	if profile.Port != 0 && (profile.Port < 1 || profile.Port > 65535) {
+		return fmt.Errorf("port must be a number between 1 and 65535")
+	}
+	return nil
+}
+
+// AddProfile adds a new pattern Host block.
+func (s *serverService) AddProfile(profile domain.Profile) error {
+	if err := validateProfile(profile); err != nil {
+		s.logger.Warnw("validation failed on profile add", "error", err, "profile", profile.Alias)
+		return err
+	}
+	err := s.serverRepository.AddProfile(profile)
+	if err != nil {
+		s.logger.Errorw("failed to add profile", "error", err, "profile", profile.Alias)
+	}
+	return err
+}
+
+// UpdateProfile updates an existing pattern Host block.
+func (s *serverService) UpdateProfile(profile domain.Profile, newProfile domain.Profile) error {
+	if err := validateProfile(newProfile); err != nil {
+		s.logger.Warnw("validation failed on profile update", "error", err, "profile", newProfile.Alias)
+		return err
+	}
+	err := s.serverRepository.UpdateProfile(profile, newProfile)
+	if err != nil {
+		s.logger.Errorw("failed to update profile", "error", err, "profile", profile.Alias)
+	}
+	return err
+}
+
+// DeleteProfile removes a pattern Host block.
+func (s *serverService) DeleteProfile(profile domain.Profile) error {
+	err := s.serverRepository.DeleteProfile(profile)
+	if err != nil {
+		s.logger.Errorw("failed to delete profile", "error", err, "profile", profile.Alias)
+	}
+	return err
+}
+
+// ListMatchBlocks returns the Match blocks of the SSH config for read-only display.
+func (s *serverService) ListMatchBlocks() ([]domain.MatchBlock, error) {
+	blocks, err := s.serverRepository.ListMatchBlocks()
+	if err != nil {
+		s.logger.Errorw("failed to list match blocks", "error", err)
+	}
+	return blocks, err
+}
+
+// Undo reverts the most recent config or metadata change and returns its description.
+func (s *serverService) Undo() (string, error) {
+	description, err := s.serverRepository.Undo()
+	if err != nil {
+		s.logger.Warnw("failed to undo", "error", err)
+	}
+	return description, err
+}
+
+// Redo reapplies the most recently undone change and returns its description.
+func (s *serverService) Redo() (string, error) {
+	description, err := s.serverRepository.Redo()
+	if err != nil {
+		s.logger.Warnw("failed to redo", "error", err)
+	}
+	return description, err
+}
+
+// ListBackups returns the backups lazyssh made of the SSH config files, newest first.
+func (s *serverService) ListBackups() ([]domain.Backup, error) {
+	backups, err := s.serverRepository.ListBackups()
+	if err != nil {
+		s.logger.Errorw("failed to list backups", "error", err)
+		return nil, err
+	}
+	return backups, nil
+}
+
+// BackupDiff returns the changes restoring the backup would make, as a unified diff.
+func (s *serverService) BackupDiff(backup domain.Backup) (string, error) {
+	diff, err := s.serverRepository.BackupDiff(backup)
+	if err != nil {
+		s.logger.Errorw("failed to diff backup", "error", err, "backup", backup.Path)
+	}
+	return diff, err
+}
+
+// RestoreBackup replaces a config file with the content of one of its backups.
+func (s *serverService) RestoreBackup(backup domain.Backup) error {
+	err := s.serverRepository.RestoreBackup(backup)
+	if err != nil {
+		s.logger.Errorw("failed to restore backup", "error", err, "backup", backup.Path)
+	}
+	return err
+}
+
+// Preview runs change, a call to other service methods, without writing anything and
+// returns a unified diff of the files it would modify.
+func (s *serverService) Preview(change func() error) (string, error) {
+	diff, err := s.serverRepository.Preview(change)
+	if err != nil {
+		s.logger.Warnw("failed to preview change", "error", err)
+	}
+	return diff, err
+}
+
+// Watch reports the config and metadata files changed outside lazyssh until stop is closed.
+func (s *serverService) Watch(stop <-chan struct{}) <-chan []string {
+	return s.serverRepository.Watch(stop)
+}
+
+// ReconcileMetadata returns the metadata entries left behind by servers renamed or removed outside lazyssh.
+func (s *serverService) ReconcileMetadata() ([]domain.MetadataOrphan, error) {
+	orphans, err := s.serverRepository.ReconcileMetadata()
+	if err != nil {
+		s.logger.Errorw("failed to reconcile metadata", "error", err)
+	}
+	return orphans, err
+}
+
+// MigrateMetadata moves the metadata of oldAlias to newAlias.
+func (s *serverService) MigrateMetadata(oldAlias, newAlias string) error {
+	err := s.serverRepository.MigrateMetadata(oldAlias, newAlias)
+	if err != nil {
+		s.logger.Errorw("failed to migrate metadata", "error", err, "old_alias", oldAlias, "alias", newAlias)
+	}
+	return err
+}
+
+// PurgeMetadata removes the metadata of alias.
+func (s *serverService) PurgeMetadata(alias string) error {
+	err := s.serverRepository.PurgeMetadata(alias)
+	if err != nil {
+		s.logger.Errorw("failed to purge metadata", "error", err, "alias", alias)
+	}
+	return err
+}
+
+// ConfigVersion returns a fingerprint of the config and metadata files that changes with their content.
+func (s *serverService) ConfigVersion() string {
+	return s.serverRepository.ConfigVersion()
+}
+
 // SSH starts an interactive SSH session to the given alias using the system's ssh client.
-func (s *serverService) SSH(alias string) error {
-	s.logger.Infow("ssh start", "alias", alias)
-	cmd := exec.Command("ssh", alias)
+// Extra args follow the alias on the ssh command line: options such as -L, then an optional
+// remote command.
+func (s *serverService) SSH(alias string, args ...string) error {
+	s.logger.Infow("ssh start", "alias", alias, "args", args)
+	cmd := exec.Command("ssh", append([]string{alias}, args...)...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -189,11 +552,15 @@ func (s *serverService) Ping(server domain.Server) (bool, time.Duration, error)
 
 	dialer := net.Dialer{Timeout: 3 * time.Second}
 	conn, err := dialer.Dial("tcp", addr)
+	result := domain.PingResult{Up: err == nil, Latency: time.Since(start), At: time.Now()}
+	s.pingsMu.Lock()
+	s.pings[server.Alias] = result
+	s.pingsMu.Unlock()
 	if err != nil {
-		return false, time.Since(start), err
+		return false, result.Latency, err
 	}
 	_ = conn.Close()
-	return true, time.Since(start), nil
+	return true, result.Latency, nil
 }
 
 // resolveSSHDestination uses `ssh -G <alias>` to extract HostName and Port from the user's SSH config.
@@ -0,0 +1,43 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestValidateProfile(t *testing.T) {
+	tests := []struct {
+		patterns string
+		wantErr  bool
+	}{
+		{"*.staging", false},
+		{"*.prod !bastion.prod", false},
+		{"db-?", false},
+		{"", true},
+		{"bastion", true},
+		{"bastion *.staging", true},
+		{"*.staging web", true},
+		{"web/*", true},
+	}
+	for _, tt := range tests {
+		err := validateProfile(domain.Profile{Server: domain.Server{Alias: tt.patterns}})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateProfile(%q) error = %v, wantErr %v", tt.patterns, err, tt.wantErr)
+		}
+	}
+}
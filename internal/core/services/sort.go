@@ -0,0 +1,294 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"cmp"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// SortField is what unpinned servers are ordered by in the server list.
+type SortField int
+
+const (
+	SortByAlias SortField = iota
+	SortByLastSeen
+	SortBySSHCount
+	SortByFrecency
+	SortByHost
+	SortByUser
+	SortByLatency
+	SortByReachability
+	SortByPinned
+)
+
+// sortFields lists the sort fields in the order of the sort menu.
+var sortFields = []SortField{
+	SortByAlias, SortByLastSeen, SortBySSHCount, SortByFrecency, SortByHost,
+	SortByUser, SortByLatency, SortByReachability, SortByPinned,
+}
+
+func (f SortField) String() string {
+	switch f {
+	case SortByLastSeen:
+		return "Last SSH"
+	case SortBySSHCount:
+		return "SSH count"
+	case SortByFrecency:
+		return "Frecency"
+	case SortByHost:
+		return "Host"
+	case SortByUser:
+		return "User"
+	case SortByLatency:
+		return "Ping latency"
+	case SortByReachability:
+		return "Reachability"
+	case SortByPinned:
+		return "Pinned order"
+	default:
+		return "Alias"
+	}
+}
+
+// key is the name of the field in saved UI state.
+func (f SortField) key() string {
+	switch f {
+	case SortByLastSeen:
+		return "last_seen"
+	case SortBySSHCount:
+		return "ssh_count"
+	case SortByFrecency:
+		return "frecency"
+	case SortByHost:
+		return "host"
+	case SortByUser:
+		return "user"
+	case SortByLatency:
+		return "latency"
+	case SortByReachability:
+		return "reachability"
+	case SortByPinned:
+		return "pinned"
+	default:
+		return "alias"
+	}
+}
+
+// defaultDesc reports whether the field is sorted in descending order when first chosen,
+// so that the most used, most recent or reachable servers come first.
+func (f SortField) defaultDesc() bool {
+	switch f {
+	case SortByLastSeen, SortBySSHCount, SortByFrecency, SortByReachability, SortByPinned:
+		return true
+	default:
+		return false
+	}
+}
+
+// SortMode is a sort field and direction. The zero value sorts by alias, A to Z.
+type SortMode struct {
+	Field SortField
+	Desc  bool
+}
+
+// NewSortMode returns the mode sorting by field in its default direction.
+func NewSortMode(field SortField) SortMode {
+	return SortMode{Field: field, Desc: field.defaultDesc()}
+}
+
+func (m SortMode) String() string {
+	if m.Desc {
+		return m.Field.String() + " ↓"
+	}
+	return m.Field.String() + " ↑"
+}
+
+// Reverse flips the direction within the current field.
+func (m SortMode) Reverse() SortMode {
+	m.Desc = !m.Desc
+	return m
+}
+
+// Key encodes the mode for saved UI state, e.g. "ssh_count:desc"; ParseSortMode decodes it.
+func (m SortMode) Key() string {
+	if m.Desc {
+		return m.Field.key() + ":desc"
+	}
+	return m.Field.key() + ":asc"
+}
+
+// ParseSortMode decodes a mode encoded by Key. A field name without a direction, such as
+// "frecency", sorts in that field's default direction.
+func ParseSortMode(s string) (SortMode, bool) {
+	key, direction, hasDirection := strings.Cut(s, ":")
+	for _, field := range sortFields {
+		if field.key() != key {
+			continue
+		}
+		switch {
+		case !hasDirection:
+			return NewSortMode(field), true
+		case direction == "asc" || direction == "desc":
+			return SortMode{Field: field, Desc: direction == "desc"}, true
+		}
+	}
+	return SortMode{}, false
+}
+
+// SortFields returns the sort fields in the order of the sort menu.
+func SortFields() []SortField {
+	return slices.Clone(sortFields)
+}
+
+// SortFieldKeys returns the names ParseSortMode accepts for the sort fields, in menu order.
+func SortFieldKeys() []string {
+	keys := make([]string, len(sortFields))
+	for i, field := range sortFields {
+		keys[i] = field.key()
+	}
+	return keys
+}
+
+// SortServers sorts servers the way the server list shows them.
+// Pinned servers are always at the top, ordered by pinned date (newest first, or in the
+// chosen direction when sorting by pinned order). Unpinned servers are sorted by the
+// selected mode. Servers without a value for the field (never connected, never pinged)
+// go to the bottom in either direction. Ties break by Alias asc.
+func SortServers(servers []domain.Server, mode SortMode) {
+	now := time.Now()
+	sort.SliceStable(servers, func(i, j int) bool {
+		si, sj := servers[i], servers[j]
+		ai, aj := strings.ToLower(si.Alias), strings.ToLower(sj.Alias)
+
+		pi, pj := !si.PinnedAt.IsZero(), !sj.PinnedAt.IsZero()
+		if pi != pj {
+			return pi
+		}
+		if pi && pj { // both pinned: by pin date, tie-break alias
+			if !si.PinnedAt.Equal(sj.PinnedAt) {
+				if mode.Field == SortByPinned && !mode.Desc {
+					return si.PinnedAt.Before(sj.PinnedAt)
+				}
+				return si.PinnedAt.After(sj.PinnedAt)
+			}
+			return ai < aj
+		}
+
+		// both unpinned
+		mi, mj := sortValueMissing(si, mode.Field), sortValueMissing(sj, mode.Field)
+		if mi != mj {
+			return mj
+		}
+		if !mi {
+			if c := compareSortField(si, sj, mode.Field, now); c != 0 {
+				if mode.Desc {
+					return c > 0
+				}
+				return c < 0
+			}
+		}
+		if mode.Field == SortByAlias && mode.Desc {
+			return ai > aj
+		}
+		return ai < aj
+	})
+}
+
+// sortValueMissing reports whether s has no value to sort by for field.
+func sortValueMissing(s domain.Server, field SortField) bool {
+	switch field {
+	case SortByLastSeen:
+		return s.LastSeen.IsZero()
+	case SortByLatency:
+		return s.LastPing.At.IsZero() || !s.LastPing.Up
+	case SortByReachability:
+		return s.LastPing.At.IsZero()
+	default:
+		return false
+	}
+}
+
+// compareSortField compares a and b by field; 0 leaves the order to the alias.
+func compareSortField(a, b domain.Server, field SortField, now time.Time) int {
+	switch field {
+	case SortByLastSeen:
+		return a.LastSeen.Compare(b.LastSeen)
+	case SortBySSHCount:
+		return cmp.Compare(a.SSHCount, b.SSHCount)
+	case SortByFrecency:
+		return cmp.Compare(frecency(a, now), frecency(b, now))
+	case SortByHost:
+		return compareHosts(a.Host, b.Host)
+	case SortByUser:
+		return strings.Compare(strings.ToLower(a.User), strings.ToLower(b.User))
+	case SortByLatency:
+		return cmp.Compare(a.LastPing.Latency, b.LastPing.Latency)
+	case SortByReachability:
+		switch {
+		case a.LastPing.Up == b.LastPing.Up:
+			return 0
+		case a.LastPing.Up:
+			return 1
+		default:
+			return -1
+		}
+	default:
+		return 0
+	}
+}
+
+// compareHosts orders IPv4 addresses numerically and before host names, which compare as text.
+func compareHosts(a, b string) int {
+	ipA, ipB := net.ParseIP(a).To4(), net.ParseIP(b).To4()
+	switch {
+	case ipA != nil && ipB != nil:
+		return bytes.Compare(ipA, ipB)
+	case ipA != nil:
+		return -1
+	case ipB != nil:
+		return 1
+	default:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+}
+
+// frecency scores how often and how recently a server was used: its SSH count weighted by
+// the age of the last connection, the way browsers rank their history.
+func frecency(s domain.Server, now time.Time) int {
+	if s.LastSeen.IsZero() {
+		return 0
+	}
+	const day = 24 * time.Hour
+	weight := 10
+	switch age := now.Sub(s.LastSeen); {
+	case age <= 4*day:
+		weight = 100
+	case age <= 14*day:
+		weight = 70
+	case age <= 31*day:
+		weight = 50
+	case age <= 90*day:
+		weight = 30
+	}
+	return s.SSHCount * weight
+}
@@ -0,0 +1,78 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestSortServers(t *testing.T) {
+	now := time.Now()
+	servers := []domain.Server{
+		{Alias: "a", Host: "10.0.0.10", User: "root", SSHCount: 20, LastSeen: now.Add(-100 * 24 * time.Hour)},
+		{Alias: "b", Host: "10.0.0.9", User: "admin", SSHCount: 3, LastSeen: now.Add(-time.Hour),
+			LastPing: domain.PingResult{Up: true, Latency: 40 * time.Millisecond, At: now}},
+		{Alias: "c", Host: "db.example.com", LastPing: domain.PingResult{At: now}},
+		{Alias: "d", Host: "2.0.0.1", User: "deploy", SSHCount: 1, LastSeen: now.Add(-10 * 24 * time.Hour),
+			LastPing: domain.PingResult{Up: true, Latency: 5 * time.Millisecond, At: now}},
+	}
+
+	tests := []struct {
+		mode SortMode
+		want string
+	}{
+		{NewSortMode(SortByHost), "d b a c"},
+		{NewSortMode(SortByHost).Reverse(), "c a b d"},
+		{NewSortMode(SortByUser), "c b d a"},
+		{NewSortMode(SortBySSHCount), "a b d c"},
+		{NewSortMode(SortByFrecency), "b a d c"},
+		{NewSortMode(SortByLastSeen), "b d a c"},
+		{NewSortMode(SortByLastSeen).Reverse(), "a d b c"},
+		{NewSortMode(SortByLatency), "d b a c"},
+		{NewSortMode(SortByLatency).Reverse(), "b d a c"},
+		{NewSortMode(SortByReachability), "b d c a"},
+	}
+	for _, tt := range tests {
+		sorted := append([]domain.Server(nil), servers...)
+		SortServers(sorted, tt.mode)
+		aliases := make([]string, len(sorted))
+		for i, s := range sorted {
+			aliases[i] = s.Alias
+		}
+		if got := strings.Join(aliases, " "); got != tt.want {
+			t.Errorf("sort by %s = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestParseSortMode(t *testing.T) {
+	for _, field := range sortFields {
+		for _, mode := range []SortMode{NewSortMode(field), NewSortMode(field).Reverse()} {
+			if got, ok := ParseSortMode(mode.Key()); !ok || got != mode {
+				t.Errorf("ParseSortMode(%q) = %v, %v, want %v", mode.Key(), got, ok, mode)
+			}
+		}
+	}
+	if got, ok := ParseSortMode("frecency"); !ok || got != NewSortMode(SortByFrecency) {
+		t.Errorf("ParseSortMode(%q) = %v, %v, want the default direction", "frecency", got, ok)
+	}
+	if _, ok := ParseSortMode("color:asc"); ok {
+		t.Errorf("ParseSortMode() accepted an unknown field")
+	}
+}
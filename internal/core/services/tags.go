@@ -0,0 +1,37 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import "strings"
+
+// EditTags removes the tags in remove and appends those in add that are missing, keeping
+// the order of the tags that stay. Tags compare case-insensitively.
+func EditTags(tags, add, remove []string) []string {
+	has := func(list []string, tag string) bool {
+		for _, t := range list {
+			if strings.EqualFold(t, tag) {
+				return true
+			}
+		}
+		return false
+	}
+	result := make([]string, 0, len(tags)+len(add))
+	for _, tag := range append(append([]string{}, tags...), add...) {
+		if !has(remove, tag) && !has(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditTags(t *testing.T) {
+	tests := []struct {
+		tags, add, remove, want []string
+	}{
+		{[]string{"prod", "web"}, []string{"eu"}, nil, []string{"prod", "web", "eu"}},
+		{[]string{"prod", "web"}, []string{"WEB"}, []string{"prod"}, []string{"web"}},
+		{[]string{"legacy"}, nil, []string{"Legacy"}, []string{}},
+		{nil, []string{"db", "db"}, nil, []string{"db"}},
+	}
+	for _, tt := range tests {
+		if got := EditTags(tt.tags, tt.add, tt.remove); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("EditTags(%v, +%v, -%v) = %v, want %v", tt.tags, tt.add, tt.remove, got, tt.want)
+		}
+	}
+}
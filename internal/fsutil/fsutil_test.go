@@ -0,0 +1,77 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new", "state.json")
+	for _, content := range []string{"first", "second"} {
+		if err := WriteFileAtomic(path, []byte(content)); err != nil {
+			t.Fatalf("WriteFileAtomic() error = %v", err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != content {
+			t.Errorf("content = %q, want %q", data, content)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("mode = %04o, want 0600", perm)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	tests := map[string]string{
+		"~":             home,
+		"~/.ssh/id_rsa": filepath.Join(home, ".ssh", "id_rsa"),
+		"~alice/key":    "~alice/key",
+		"/etc/ssh/key":  "/etc/ssh/key",
+		"keys/~/id":     "keys/~/id",
+	}
+	for path, want := range tests {
+		if got := ExpandHome(path); got != want {
+			t.Errorf("ExpandHome(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestShortenHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	tests := map[string]string{
+		home:                        "~",
+		filepath.Join(home, ".ssh"): "~/.ssh",
+		home + "-other/config":      home + "-other/config",
+		"/etc/ssh/ssh_config":       "/etc/ssh/ssh_config",
+	}
+	for path, want := range tests {
+		if got := ShortenHome(path); got != want {
+			t.Errorf("ShortenHome(%q) = %q, want %q", path, got, want)
+		}
+	}
+}